@@ -0,0 +1,312 @@
+// Package esfilter translates parsed qparams queries to
+// Elasticsearch and OpenSearch query DSL
+//
+// Results are JSON serializable maps ready to be used as the
+// search request body
+package esfilter
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/tonto/qparams"
+)
+
+// Field holds mapping hints of an index field
+type Field struct {
+	// Name is the index field name, filter field name is used if empty
+	Name string
+
+	// Text reports whether field is analyzed text field, exact
+	// matches use match_phrase queries unless Keyword is set
+	Text bool
+
+	// Keyword is the keyword sub field of a text field relative to
+	// its index field name eg. keyword for name.keyword, used for
+	// exact matches and sorting
+	Keyword string
+}
+
+// Options configure query translation
+type Options struct {
+	// Fields holds mapping hints of allowed fields, fields without
+	// Name hint are index fields of the same name unless Mapper
+	// is set. Fields not present are keyword fields if mapped by
+	// Mapper, and rejected otherwise
+	Fields map[string]Field
+
+	// Mapper resolves index field names of fields without Name
+	// hint eg. qparams.FieldMapOf[Order]("json"), fields it does
+	// not map are rejected
+	Mapper qparams.FieldMapper
+}
+
+var (
+	// ErrUnsupportedOp is returned for conditions with Custom operator
+	ErrUnsupportedOp = errors.New("esfilter: unsupported operator")
+
+	// ErrUnknownField is returned for fields neither present
+	// in Fields nor mapped by Mapper
+	ErrUnknownField = errors.New("esfilter: unknown field")
+
+	// ErrNotSortable is returned for sorting by text fields
+	// without keyword sub field
+	ErrNotSortable = errors.New("esfilter: field is not sortable")
+)
+
+var ranges = map[qparams.Op]string{
+	qparams.Gt:  "gt",
+	qparams.Gte: "gte",
+	qparams.Lt:  "lt",
+	qparams.Lte: "lte",
+}
+
+// Search translates q to search request body with query,
+// sort, from and size
+func Search(q qparams.Query, opts Options) (map[string]any, error) {
+	query, err := Query(q.Filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	body := map[string]any{"query": query}
+
+	if len(q.Sort) > 0 {
+		sort := []any{}
+
+		for _, s := range q.Sort {
+			f, err := opts.field(s.Field)
+			if err != nil {
+				return nil, err
+			}
+
+			name := f.Name
+			if f.Text {
+				if f.Keyword == "" {
+					return nil, fmt.Errorf("%w %s", ErrNotSortable, s.Field)
+				}
+				name = f.Keyword
+			}
+
+			order := "asc"
+			if s.Desc {
+				order = "desc"
+			}
+
+			sort = append(sort, map[string]any{name: map[string]any{"order": order}})
+		}
+
+		body["sort"] = sort
+	}
+
+	if q.Offset > 0 {
+		body["from"] = q.Offset
+	}
+
+	if q.Limit > 0 {
+		body["size"] = q.Limit
+	}
+
+	return body, nil
+}
+
+// Query translates filter f to query clause, empty filter
+// yields match_all query
+func Query(f qparams.Filter, opts Options) (map[string]any, error) {
+	return opts.node(f.Expr())
+}
+
+// field returns hints of field name with index field name and
+// keyword sub field resolved
+func (o Options) field(name string) (Field, error) {
+	f, allowed := o.Fields[name]
+
+	switch {
+	case f.Name != "":
+	case o.Mapper != nil:
+		mapped, ok := o.Mapper.MapField(name)
+		if !ok {
+			return Field{}, fmt.Errorf("%w %s", ErrUnknownField, name)
+		}
+		f.Name = mapped
+	case allowed:
+		f.Name = name
+	default:
+		return Field{}, fmt.Errorf("%w %s", ErrUnknownField, name)
+	}
+
+	if f.Keyword != "" {
+		f.Keyword = f.Name + "." + f.Keyword
+	}
+
+	return f, nil
+}
+
+func (o Options) node(n qparams.Node) (map[string]any, error) {
+	var nodes []qparams.Node
+	var occur string
+
+	switch n := n.(type) {
+	case qparams.And:
+		nodes, occur = n, "filter"
+	case qparams.Or:
+		nodes, occur = n, "should"
+	case qparams.Not:
+		q, err := o.node(n.Node)
+		if err != nil {
+			return nil, err
+		}
+		return mustNot(q), nil
+	case qparams.Condition:
+		return o.condition(n)
+	}
+
+	if len(nodes) == 0 {
+		return map[string]any{"match_all": map[string]any{}}, nil
+	}
+
+	if len(nodes) == 1 {
+		return o.node(nodes[0])
+	}
+
+	queries := make([]any, 0, len(nodes))
+
+	for _, n := range nodes {
+		q, err := o.node(n)
+		if err != nil {
+			return nil, err
+		}
+
+		queries = append(queries, q)
+	}
+
+	return boolQuery(occur, queries), nil
+}
+
+func boolQuery(occur string, queries []any) map[string]any {
+	b := map[string]any{occur: queries}
+	if occur == "should" {
+		b["minimum_should_match"] = 1
+	}
+
+	return map[string]any{"bool": b}
+}
+
+func mustNot(q map[string]any) map[string]any {
+	return map[string]any{"bool": map[string]any{"must_not": []any{q}}}
+}
+
+func (o Options) condition(c qparams.Condition) (map[string]any, error) {
+	f, err := o.field(c.Field)
+	if err != nil {
+		return nil, err
+	}
+
+	var q map[string]any
+
+	switch {
+	case c.Pattern != nil:
+		q = f.pattern(c.Pattern)
+	case c.Op == qparams.IsNull:
+		q = map[string]any{"exists": map[string]any{"field": f.Name}}
+		if c.Not {
+			return q, nil
+		}
+		return mustNot(q), nil
+	case c.Op == qparams.In:
+		values := []any{}
+		for _, v := range c.TypedValues() {
+			values = append(values, value(v))
+		}
+
+		q = f.terms(values)
+	case c.Range != nil:
+		bounds := map[string]any{}
+
+		lower, upper := c.TypedBounds()
+		if lower != nil {
+			bounds["gte"] = value(lower)
+		}
+
+		if upper != nil {
+			bounds["lte"] = value(upper)
+		}
+
+		q = map[string]any{"range": map[string]any{f.Name: bounds}}
+	case c.Op == qparams.Eq || c.Op == qparams.Ne:
+		q = f.term(value(c.TypedValue()))
+		if c.Op == qparams.Ne {
+			q = mustNot(q)
+		}
+	default:
+		op, ok := ranges[c.Op]
+		if !ok {
+			return nil, fmt.Errorf("%w %s", ErrUnsupportedOp, c.RawOp)
+		}
+
+		q = map[string]any{"range": map[string]any{f.Name: map[string]any{op: value(c.TypedValue())}}}
+	}
+
+	if c.Not {
+		return mustNot(q), nil
+	}
+
+	return q, nil
+}
+
+func (f Field) exact() (string, bool) {
+	if !f.Text {
+		return f.Name, true
+	}
+
+	return f.Keyword, f.Keyword != ""
+}
+
+func (f Field) term(v any) map[string]any {
+	if name, ok := f.exact(); ok {
+		return map[string]any{"term": map[string]any{name: v}}
+	}
+
+	return map[string]any{"match_phrase": map[string]any{f.Name: v}}
+}
+
+func (f Field) terms(values []any) map[string]any {
+	if name, ok := f.exact(); ok {
+		return map[string]any{"terms": map[string]any{name: values}}
+	}
+
+	queries := make([]any, 0, len(values))
+	for _, v := range values {
+		queries = append(queries, f.term(v))
+	}
+
+	return boolQuery("should", queries)
+}
+
+func (f Field) pattern(p *qparams.Pattern) map[string]any {
+	name, ok := f.exact()
+
+	if !ok {
+		switch p.Mode {
+		case qparams.MatchContains:
+			return map[string]any{"match_phrase": map[string]any{f.Name: p.Parts[0]}}
+		case qparams.MatchPrefix:
+			return map[string]any{"match_phrase_prefix": map[string]any{f.Name: p.Parts[0]}}
+		}
+
+		name = f.Name
+	}
+
+	return map[string]any{"wildcard": map[string]any{name: map[string]any{
+		"value":            p.Wildcard(),
+		"case_insensitive": p.Fold,
+	}}}
+}
+
+func value(v any) any {
+	if d, ok := v.(qparams.Decimal); ok {
+		return string(d)
+	}
+
+	return v
+}
@@ -0,0 +1,178 @@
+package esfilter
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tonto/qparams"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+var fields = map[string]Field{
+	"name":    {Text: true, Keyword: "raw"},
+	"title":   {Text: true},
+	"email":   {Name: "contact.email"},
+	"status":  {},
+	"amount":  {},
+	"age":     {},
+	"deleted": {},
+	"created": {},
+}
+
+// cond returns condition of field, operator and value
+func cond(field string, op qparams.Op, value string) qparams.Condition {
+	return qparams.Condition{Field: field, Op: op, Value: value}
+}
+
+// pattern returns pattern matching condition of field
+func pattern(field string, op qparams.Op, mode qparams.MatchMode, fold bool, parts ...string) qparams.Condition {
+	return qparams.Condition{Field: field, Op: op, Pattern: &qparams.Pattern{Mode: mode, Parts: parts, Fold: fold}}
+}
+
+// where returns query filtering by expression root
+func where(root qparams.Node) qparams.Query {
+	return qparams.Query{Filter: qparams.Filter{Root: root}}
+}
+
+func golden(t *testing.T, name string, v interface{}) {
+	got, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		t.Fatalf("Could not marshal %s: %v", name, err)
+	}
+
+	path := filepath.Join("testdata", name+".golden.json")
+
+	if *update {
+		if err := os.WriteFile(path, append(got, '\n'), 0644); err != nil {
+			t.Fatalf("Could not update %s: %v", path, err)
+		}
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Could not read %s: %v", path, err)
+	}
+
+	if string(want) != string(got)+"\n" {
+		t.Fatalf("Incorrect query for %s\nWANT: %s\nGOT:  %s", name, want, got)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	mapped := where(qparams.And{cond("status", qparams.Eq, "paid"), cond("email", qparams.Eq, "a@b.c"), cond("name", qparams.Eq, "jo")})
+	mapped.Sort = qparams.Sort{{Field: "status"}}
+
+	sorted := qparams.Query{Sort: qparams.Sort{{Field: "name", Desc: true}, {Field: "age"}}, Limit: 10, Offset: 20}
+
+	table := []struct {
+		Name   string
+		Query  qparams.Query
+		Mapper qparams.FieldMapper
+	}{
+		{
+			Name: "comparisons",
+			Query: where(qparams.And{
+				qparams.Condition{Field: "amount", Op: qparams.Gte, Value: "10.5", Typed: qparams.Decimal("10.5")},
+				cond("status", qparams.Eq, "paid"),
+				qparams.Condition{Field: "age", Op: qparams.Ne, Value: "3", Typed: 3},
+				qparams.Condition{Field: "age", Op: qparams.Lt, Value: "65", Typed: 65},
+			}),
+		},
+		{
+			Name: "groups",
+			Query: where(qparams.And{
+				qparams.Or{cond("status", qparams.Eq, "paid"), cond("status", qparams.Eq, "refunded")},
+				qparams.Not{Node: qparams.Condition{Field: "age", Op: qparams.Gt, Value: "18", Typed: 18}},
+			}),
+		},
+		{
+			Name: "sets",
+			Query: where(qparams.And{
+				qparams.Condition{Field: "status", Op: qparams.In, Values: []string{"paid", "refunded"}},
+				qparams.Condition{Field: "age", Op: qparams.In, Not: true, Values: []string{"1", "2"}, Typed: []int{1, 2}},
+				qparams.Condition{Field: "title", Op: qparams.In, Values: []string{"a", "b"}},
+			}),
+		},
+		{
+			Name: "null",
+			Query: where(qparams.And{
+				qparams.Condition{Field: "deleted", Op: qparams.IsNull},
+				qparams.Condition{Field: "created", Op: qparams.IsNull, Not: true},
+			}),
+		},
+		{
+			Name: "ranges",
+			Query: where(qparams.And{
+				qparams.Condition{Field: "age", Op: qparams.Between, Range: &qparams.Bounds{Lower: "18", Upper: "65"}, Typed: [2]interface{}{18, 65}},
+				qparams.Condition{Field: "amount", Op: qparams.Between, Range: &qparams.Bounds{Upper: "100"}, Typed: [2]interface{}{nil, qparams.Decimal("100")}},
+			}),
+		},
+		{
+			Name: "patterns",
+			Query: where(qparams.And{
+				pattern("name", qparams.Like, qparams.MatchLike, false, "Jo", ""),
+				pattern("email", qparams.IContains, qparams.MatchContains, true, "@Example"),
+				pattern("title", qparams.Contains, qparams.MatchContains, false, "go"),
+				pattern("title", qparams.StartsWith, qparams.MatchPrefix, false, "intro"),
+			}),
+		},
+		{
+			Name:  "text",
+			Query: where(qparams.And{cond("name", qparams.Eq, "john"), cond("title", qparams.Eq, "go")}),
+		},
+		{
+			Name:   "mapped",
+			Query:  mapped,
+			Mapper: qparams.FieldMap{"status": "order.status", "name": "customer.name"},
+		},
+		{
+			Name:  "sort",
+			Query: sorted,
+		},
+	}
+
+	for _, c := range table {
+		body, err := Search(c.Query, Options{Fields: fields, Mapper: c.Mapper})
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", c.Name, err)
+		}
+
+		golden(t, c.Name, body)
+	}
+}
+
+func TestSearchErrors(t *testing.T) {
+	if _, err := Search(qparams.Query{Sort: qparams.Sort{{Field: "title"}}}, Options{Fields: fields}); !errors.Is(err, ErrNotSortable) {
+		t.Fatalf("Incorrect error WANT: %v GOT: %v", ErrNotSortable, err)
+	}
+
+	f := qparams.Filter{Conditions: []qparams.Condition{
+		{Field: "age", Op: qparams.Custom, RawOp: "~~", Value: "1"},
+	}}
+
+	if _, err := Query(f, Options{Fields: fields}); !errors.Is(err, ErrUnsupportedOp) {
+		t.Fatalf("Incorrect error WANT: %v GOT: %v", ErrUnsupportedOp, err)
+	}
+
+	opts := Options{Fields: fields, Mapper: qparams.FieldMap{"status": "order.status"}}
+
+	q := where(cond("status", qparams.Eq, "paid"))
+	q.Sort = qparams.Sort{{Field: "secret"}}
+
+	if _, err := Search(q, opts); !errors.Is(err, ErrUnknownField) {
+		t.Fatalf("Incorrect error WANT: %v GOT: %v", ErrUnknownField, err)
+	}
+
+	if _, err := Search(where(cond("secret", qparams.Eq, "x")), Options{Fields: fields}); !errors.Is(err, ErrUnknownField) {
+		t.Fatalf("Incorrect error WANT: %v GOT: %v", ErrUnknownField, err)
+	}
+
+	if _, err := Search(where(cond("age", qparams.Eq, "3")), opts); !errors.Is(err, ErrUnknownField) {
+		t.Fatalf("Fields not mapped by Mapper should be rejected GOT: %v", err)
+	}
+}
@@ -0,0 +1,457 @@
+package qparams
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrUnknownField is returned when evaluating filter or sort
+// fields not present in the evaluated type
+var ErrUnknownField = errors.New("Unknown field")
+
+// ErrUnsupportedOp is returned when evaluating conditions whose
+// operator does not apply to the field type
+var ErrUnsupportedOp = errors.New("Unsupported operator")
+
+// evalField is a filterable field of evaluated struct type
+type evalField struct {
+	index []int
+	vt    valueType
+}
+
+// evaluator evaluates filters and sorts against values of a
+// struct type, fields are named the same way as FilterOf schema
+// fields, lowercased or set with name tag. Mapped fields are
+// resolved by Go field names as well
+type evaluator struct {
+	fields map[string]evalField
+	names  map[string]evalField
+}
+
+// evaluators caches evaluators by type
+var evaluators sync.Map
+
+// getEvaluator returns cached evaluator of t, building it on first use
+func getEvaluator(t reflect.Type) (*evaluator, error) {
+	if e, ok := evaluators.Load(t); ok {
+		return e.(*evaluator), nil
+	}
+
+	e, err := newEvaluator(t)
+	if err != nil {
+		return nil, err
+	}
+
+	actual, _ := evaluators.LoadOrStore(t, e)
+
+	return actual.(*evaluator), nil
+}
+
+func newEvaluator(t reflect.Type) (*evaluator, error) {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("Evaluated type %s must be a struct", t)
+	}
+
+	e := &evaluator{
+		fields: make(map[string]evalField),
+		names:  make(map[string]evalField),
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name := strings.ToLower(field.Name)
+		if tagName := getTag("name", field); tagName != "" {
+			name = tagName
+		}
+
+		if field.Type.Kind() == reflect.Pointer {
+			field.Type = field.Type.Elem()
+		}
+
+		vt, ok := fieldValueType(field)
+		if !ok {
+			continue
+		}
+
+		f := evalField{index: []int{i}, vt: vt}
+
+		e.fields[name] = f
+		e.names[strings.ToLower(field.Name)] = f
+	}
+
+	return e, nil
+}
+
+// Match reports whether item satisfies filter f. Field names are
+// resolved through T struct fields (lowercased or set with name tag),
+// pointer fields are null if nil. Condition values are converted
+// to field types, so untyped filters can be matched as well.
+// Public field names are mapped to T field names eg. CreatedAt
+// with mappers, unmapped fields yield ErrUnknownField.
+// Nil items never match
+func Match[T any](f Filter, item T, mappers ...FieldMapper) (bool, error) {
+	e, err := getEvaluator(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return false, err
+	}
+
+	for _, m := range mappers {
+		if f, err = f.Map(m); err != nil {
+			return false, err
+		}
+	}
+
+	v := reflect.ValueOf(item)
+	if isNilItem(v) {
+		return false, nil
+	}
+
+	t, err := e.node(f.Expr(), v)
+
+	return t == truthy, err
+}
+
+// Apply returns items matching q filter, sorted by q sort and
+// paginated with q limit and offset. Sort is stable and nil
+// pointer fields sort as the lowest values. Nil items are dropped
+// and negative offset is ignored. Public field names are mapped
+// to T field names eg. CreatedAt with mappers, unmapped fields
+// yield ErrUnknownField
+func Apply[T any](items []T, q Query, mappers ...FieldMapper) ([]T, error) {
+	e, err := getEvaluator(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range mappers {
+		if q, err = q.Map(m); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, s := range q.Sort {
+		if _, ok := e.field(s.Field); !ok {
+			return nil, fmt.Errorf("%w %s", ErrUnknownField, s.Field)
+		}
+	}
+
+	expr := q.Filter.Expr()
+	result := []T{}
+
+	for _, item := range items {
+		v := reflect.ValueOf(item)
+		if isNilItem(v) {
+			continue
+		}
+
+		t, err := e.node(expr, v)
+		if err != nil {
+			return nil, err
+		}
+
+		if t == truthy {
+			result = append(result, item)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return e.less(q.Sort, reflect.ValueOf(result[i]), reflect.ValueOf(result[j]))
+	})
+
+	if q.Offset >= len(result) {
+		return []T{}, nil
+	}
+
+	if q.Offset > 0 {
+		result = result[q.Offset:]
+	}
+
+	if q.Limit > 0 && q.Limit < len(result) {
+		result = result[:q.Limit]
+	}
+
+	return result, nil
+}
+
+// isNilItem reports whether evaluated item is a nil pointer
+func isNilItem(v reflect.Value) bool {
+	return v.Kind() == reflect.Pointer && v.IsNil()
+}
+
+// truth is result of evaluated node, conditions on null fields
+// are unknown as in SQL three-valued logic, so negating them
+// does not turn them into matches
+type truth int
+
+const (
+	falsy truth = iota
+	truthy
+	unknown
+)
+
+func truthOf(ok bool) truth {
+	if ok {
+		return truthy
+	}
+
+	return falsy
+}
+
+// not negates t, unknown stays unknown
+func (t truth) not() truth {
+	switch t {
+	case truthy:
+		return falsy
+	case falsy:
+		return truthy
+	}
+
+	return unknown
+}
+
+func (e *evaluator) node(n Node, v reflect.Value) (truth, error) {
+	switch n := n.(type) {
+	case And:
+		result := truthy
+		for _, n := range n {
+			t, err := e.node(n, v)
+			if err != nil || t == falsy {
+				return falsy, err
+			}
+
+			if t == unknown {
+				result = unknown
+			}
+		}
+		return result, nil
+	case Or:
+		result := truthOf(len(n) == 0)
+		for _, n := range n {
+			t, err := e.node(n, v)
+			if err != nil || t == truthy {
+				return t, err
+			}
+
+			if t == unknown {
+				result = unknown
+			}
+		}
+		return result, nil
+	case Not:
+		t, err := e.node(n.Node, v)
+		return t.not(), err
+	case Condition:
+		t, err := e.condition(n, v)
+		if n.Not {
+			t = t.not()
+		}
+		return t, err
+	}
+
+	return truthy, nil
+}
+
+// field returns evaluated field by name, names mapped to
+// T field names eg. CreatedAt are matched case-insensitively
+func (e *evaluator) field(name string) (evalField, bool) {
+	if f, ok := e.fields[name]; ok {
+		return f, true
+	}
+
+	lower := strings.ToLower(name)
+	if f, ok := e.fields[lower]; ok {
+		return f, true
+	}
+
+	f, ok := e.names[lower]
+
+	return f, ok
+}
+
+// value returns field value of item v converted the same way as
+// condition values, nil if it is a nil pointer
+func (e *evaluator) value(field string, v reflect.Value) (interface{}, evalField, error) {
+	f, ok := e.field(field)
+	if !ok {
+		return nil, f, fmt.Errorf("%w %s", ErrUnknownField, field)
+	}
+
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+
+	fv := v.FieldByIndex(f.index)
+	if fv.Kind() == reflect.Pointer {
+		if fv.IsNil() {
+			return nil, f, nil
+		}
+		fv = fv.Elem()
+	}
+
+	switch f.vt.kind {
+	case "int":
+		return int(fv.Int()), f, nil
+	case "float":
+		return fv.Float(), f, nil
+	case "bool":
+		return fv.Bool(), f, nil
+	case "string", "enum":
+		return fv.String(), f, nil
+	}
+
+	return fv.Interface(), f, nil
+}
+
+// operand returns condition value typed as field value, raw value
+// is converted if typed value has a different type
+func operand(f evalField, typed interface{}, raw string) (interface{}, error) {
+	if f.vt.matches(typed) {
+		return typed, nil
+	}
+
+	return f.vt.convert(raw)
+}
+
+// condition evaluates c against item v, conditions other
+// than IsNull on null fields are unknown
+func (e *evaluator) condition(c Condition, v reflect.Value) (truth, error) {
+	value, f, err := e.value(c.Field, v)
+	if err != nil {
+		return falsy, err
+	}
+
+	if c.Op == IsNull {
+		return truthOf(value == nil), nil
+	}
+
+	if value == nil {
+		return unknown, nil
+	}
+
+	ok, err := e.compare(c, f, value)
+
+	return truthOf(ok), err
+}
+
+// compare reports whether non-null field value satisfies c
+func (e *evaluator) compare(c Condition, f evalField, value interface{}) (bool, error) {
+	switch {
+	case c.Pattern != nil:
+		s, ok := value.(string)
+		if !ok {
+			return false, fmt.Errorf("%w %s for %s field %s", ErrUnsupportedOp, c.RawOp, f.vt, c.Field)
+		}
+		return c.Pattern.Match(s), nil
+	case c.Op == In:
+		typed := c.TypedValues()
+		for i, raw := range c.Values {
+			member, err := operand(f, typed[i], raw)
+			if err != nil {
+				return false, err
+			}
+
+			if r, _ := compareValues(value, member); r == 0 {
+				return true, nil
+			}
+		}
+		return false, nil
+	case c.Range != nil:
+		lower, upper := c.TypedBounds()
+
+		if c.Range.Lower != "" {
+			bound, err := operand(f, lower, c.Range.Lower)
+			if err != nil {
+				return false, err
+			}
+
+			if r, _ := compareValues(value, bound); r < 0 {
+				return false, nil
+			}
+		}
+
+		if c.Range.Upper != "" {
+			bound, err := operand(f, upper, c.Range.Upper)
+			if err != nil {
+				return false, err
+			}
+
+			if r, _ := compareValues(value, bound); r > 0 {
+				return false, nil
+			}
+		}
+
+		return true, nil
+	}
+
+	want, err := operand(f, c.TypedValue(), c.Value)
+	if err != nil {
+		return false, err
+	}
+
+	r, ok := compareValues(value, want)
+	if !ok {
+		return false, fmt.Errorf("%w %s for %s field %s", ErrUnsupportedOp, c.RawOp, f.vt, c.Field)
+	}
+
+	switch c.Op {
+	case Eq:
+		return r == 0, nil
+	case Ne:
+		return r != 0, nil
+	case Gt:
+		return r > 0, nil
+	case Gte:
+		return r >= 0, nil
+	case Lt:
+		return r < 0, nil
+	case Lte:
+		return r <= 0, nil
+	}
+
+	return false, fmt.Errorf("%w %s", ErrUnsupportedOp, c.RawOp)
+}
+
+// less reports whether a sorts before b by sort fields
+func (e *evaluator) less(s Sort, a, b reflect.Value) bool {
+	for _, field := range s {
+		va, _, _ := e.value(field.Field, a)
+		vb, _, _ := e.value(field.Field, b)
+
+		var r int
+
+		switch {
+		case va == nil && vb == nil:
+			continue
+		case va == nil:
+			r = -1
+		case vb == nil:
+			r = 1
+		default:
+			r, _ = compareValues(va, vb)
+		}
+
+		if r == 0 {
+			continue
+		}
+
+		if field.Desc {
+			return r > 0
+		}
+
+		return r < 0
+	}
+
+	return false
+}
@@ -0,0 +1,181 @@
+package qparams
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type evalOrder struct {
+	ID      int
+	Amount  Decimal
+	Status  string
+	Paid    bool
+	Score   float64
+	Created time.Time `qparams:"layout:2006-01-02"`
+	Deleted *time.Time
+	Name    string `qparams:"name:customer"`
+}
+
+func evalOrders() []evalOrder {
+	deleted := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	return []evalOrder{
+		{ID: 1, Amount: "10.50", Status: "paid", Paid: true, Score: 1.5, Created: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Name: "John Doe"},
+		{ID: 2, Amount: "100", Status: "refunded", Score: 3, Created: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), Deleted: &deleted, Name: "jane roe"},
+		{ID: 3, Amount: "9.99", Status: "paid", Paid: true, Score: 2, Created: time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC), Name: "Johnny"},
+		{ID: 4, Amount: "55", Status: "void", Score: 3, Created: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Name: "Ann"},
+	}
+}
+
+// cond returns condition of field, operator and raw value
+func cond(field string, op Op, value string) Condition {
+	c := Condition{Field: field, Op: op, Value: value}
+
+	if mode, fold, ok := op.match(); ok {
+		p := newPattern(mode, fold, value)
+		c.Pattern = &p
+	}
+
+	return c
+}
+
+// where returns query filtering by expression root
+func where(root Node) Query {
+	return Query{Filter: Filter{Root: root}}
+}
+
+func TestApply(t *testing.T) {
+	notDeleted := Condition{Field: "deleted", Op: IsNull, Not: true}
+
+	table := []struct {
+		name  string
+		query Query
+		ids   []int
+	}{
+		{name: "comparison", query: where(cond("amount", Gte, "10")), ids: []int{1, 2, 4}},
+		{name: "and", query: where(And{cond("status", Eq, "paid"), cond("paid", Eq, "true")}), ids: []int{1, 3}},
+		{name: "in", query: where(And{Condition{Field: "status", Op: In, Values: []string{"paid", "void"}}, cond("id", Ne, "1")}), ids: []int{3, 4}},
+		{name: "not in", query: where(Condition{Field: "status", Op: In, Not: true, Values: []string{"paid", "void"}}), ids: []int{2}},
+		{name: "time range", query: where(Condition{Field: "created", Op: Between, Range: &Bounds{Lower: "2024-01-01", Upper: "2024-02-01"}}), ids: []int{1, 2}},
+		{name: "open range", query: where(Condition{Field: "score", Op: Between, Range: &Bounds{Upper: "2"}}), ids: []int{1, 3}},
+		{name: "null", query: where(Condition{Field: "deleted", Op: IsNull}), ids: []int{1, 3, 4}},
+		{name: "not null", query: where(notDeleted), ids: []int{2}},
+		{name: "patterns", query: where(And{cond("customer", Like, "Jo*"), cond("customer", IContains, "DOE")}), ids: []int{1}},
+		{name: "groups", query: where(And{Or{cond("status", Eq, "void"), cond("amount", Lt, "10")}, Not{cond("id", Eq, "4")}}), ids: []int{3}},
+		{name: "sort", query: Query{Sort: Sort{{Field: "score", Desc: true}, {Field: "id"}}}, ids: []int{2, 4, 3, 1}},
+		{name: "sort nulls", query: Query{Sort: Sort{{Field: "deleted"}, {Field: "id", Desc: true}}}, ids: []int{4, 3, 1, 2}},
+		{name: "paginated", query: Query{Sort: Sort{{Field: "created", Desc: true}}, Limit: 2, Offset: 1}, ids: []int{2, 1}},
+		{name: "offset out of range", query: Query{Filter: Filter{Root: cond("id", Gt, "1")}, Offset: 5}, ids: []int{}},
+		{name: "negative offset", query: Query{Sort: Sort{{Field: "id"}}, Offset: -1, Limit: 2}, ids: []int{1, 2}},
+	}
+
+	for _, c := range table {
+		orders, err := Apply(evalOrders(), c.query)
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", c.name, err)
+		}
+
+		ids := []int{}
+		for _, o := range orders {
+			ids = append(ids, o.ID)
+		}
+
+		if !reflect.DeepEqual(ids, c.ids) {
+			t.Fatalf("Incorrect result for %s WANT: %v GOT: %v", c.name, c.ids, ids)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	o := &evalOrders()[0]
+
+	amount := Condition{Field: "amount", Op: Between, Range: &Bounds{Lower: "10", Upper: "11"}, Typed: [2]interface{}{Decimal("10"), Decimal("11")}}
+
+	ok, err := Match(Filter{Root: And{amount, cond("customer", IContains, "john")}}, o)
+	if err != nil || !ok {
+		t.Fatalf("Expected match GOT: %v %v", ok, err)
+	}
+
+	ok, err = Match(Filter{Conditions: []Condition{cond("amount", Gt, "10.5")}}, *o)
+	if err != nil || ok {
+		t.Fatalf("Expected no match GOT: %v %v", ok, err)
+	}
+
+	ok, err = Match(Filter{Root: Condition{Field: "deleted", Op: IsNull, Not: true}}, (*evalOrder)(nil))
+	if err != nil || ok {
+		t.Fatalf("Nil item should not match GOT: %v %v", ok, err)
+	}
+}
+
+func TestApplyPointers(t *testing.T) {
+	orders := evalOrders()
+	items := []*evalOrder{&orders[0], nil, &orders[2]}
+
+	q := where(Condition{Field: "id", Op: Eq, Value: "3", Not: true})
+	q.Sort = Sort{{Field: "id", Desc: true}}
+
+	got, err := Apply(items, q)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(got) != 1 || got[0].ID != 1 {
+		t.Fatalf("Nil items should be dropped GOT: %v", got)
+	}
+}
+
+func TestApplyErrors(t *testing.T) {
+	table := []struct {
+		query Query
+		err   error
+	}{
+		{query: where(cond("password", Eq, "secret")), err: ErrUnknownField},
+		{query: Query{Sort: Sort{{Field: "password"}}}, err: ErrUnknownField},
+		{query: where(cond("id", Like, "Jo*")), err: ErrUnsupportedOp},
+	}
+
+	for i, c := range table {
+		if _, err := Apply(evalOrders(), c.query); !errors.Is(err, c.err) {
+			t.Fatalf("Incorrect error for case %d WANT: %v GOT: %v", i, c.err, err)
+		}
+	}
+
+	if _, err := Apply(evalOrders(), where(cond("id", Eq, "x"))); err == nil {
+		t.Fatalf("Expected conversion error")
+	}
+}
+
+func TestMatchNullFields(t *testing.T) {
+	type probeItem struct {
+		Status *string
+	}
+
+	paid := cond("status", Eq, "paid")
+	notPaid := paid
+	notPaid.Not = true
+
+	table := []struct {
+		name string
+		root Node
+	}{
+		{"negated comparison", notPaid},
+		{"not in", Condition{Field: "status", Op: In, Not: true, Values: []string{"paid"}}},
+		{"negated group", Not{paid}},
+		{"not equal", cond("status", Ne, "paid")},
+		{"negated or", Not{Or{paid, cond("status", Eq, "void")}}},
+	}
+
+	for _, c := range table {
+		ok, err := Match(Filter{Root: c.root}, probeItem{})
+		if err != nil || ok {
+			t.Fatalf("Null field should not match %s GOT: %v %v", c.name, ok, err)
+		}
+	}
+
+	ok, err := Match(Filter{Root: Or{notPaid, Condition{Field: "status", Op: IsNull}}}, probeItem{})
+	if err != nil || !ok {
+		t.Fatalf("Expected match GOT: %v %v", ok, err)
+	}
+}
@@ -0,0 +1,318 @@
+package qparams
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Node is a node of a parsed filter expression, one of
+// And, Or, Not or Condition
+type Node interface {
+	node()
+}
+
+type (
+	// And matches if all of its nodes match
+	And []Node
+
+	// Or matches if any of its nodes match
+	Or []Node
+
+	// Not negates its node
+	Not struct {
+		Node Node
+	}
+)
+
+func (And) node()       {}
+func (Or) node()        {}
+func (Not) node()       {}
+func (Condition) node() {}
+
+const (
+	defaultOrToken  = "|"
+	defaultGroup    = "()"
+	defaultMaxDepth = 8
+	defaultMaxNodes = 100
+)
+
+// exprOptions holds filter expression grammar options
+// read from field tags
+type exprOptions struct {
+	enabled  bool
+	or       string
+	open     string
+	close    string
+	maxDepth int
+	maxNodes int
+}
+
+func getExprOptions(sField reflect.StructField) (exprOptions, error) {
+	opts := exprOptions{
+		or:       defaultOrToken,
+		open:     defaultGroup[:1],
+		close:    defaultGroup[1:],
+		maxDepth: defaultMaxDepth,
+		maxNodes: defaultMaxNodes,
+	}
+
+	if e := getTag("expr", sField); e != "" {
+		enabled, err := strconv.ParseBool(e)
+		if err != nil {
+			return opts, fmt.Errorf("Field %s has invalid expr tag %s", sField.Name, e)
+		}
+
+		opts.enabled = enabled
+	}
+
+	if or := getTag("or", sField); or != "" {
+		opts.or = or
+	}
+
+	if group := getTag("group", sField); group != "" {
+		if len(group) != 2 {
+			return opts, fmt.Errorf("Field %s has invalid group tag %s", sField.Name, group)
+		}
+
+		opts.open, opts.close = group[:1], group[1:]
+	}
+
+	if v := getTag("maxnodes", sField); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return opts, fmt.Errorf("Field %s has invalid maxnodes tag %s", sField.Name, v)
+		}
+
+		opts.maxNodes = n
+	}
+
+	return opts, nil
+}
+
+// exprParser is a recursive descent parser of filter expressions:
+//
+//	or     = and { OR and }
+//	and    = factor { SEP factor }
+//	factor = { NOT } OPEN or CLOSE | condition
+//
+// Negated conditions are not wrapped in Not, their Not field is set
+type exprParser struct {
+	input  string
+	pos    int
+	depth  int
+	nodes  int
+	sField reflect.StructField
+	opts   filterOptions
+
+	conditions []Condition
+	errs       Errors
+	err        error
+}
+
+func (f *Filter) parseExpr(sField reflect.StructField, queryValue string, opts filterOptions) error {
+	p := &exprParser{
+		input:  queryValue,
+		sField: sField,
+		opts:   opts,
+	}
+
+	f.Conditions, f.Root = nil, nil
+
+	if queryValue == "" {
+		return nil
+	}
+
+	root := p.parseOr()
+	if p.err == nil && p.pos < len(p.input) {
+		p.fail(CodeExprUnexpectedToken, p.input[p.pos:p.pos+1])
+	}
+
+	if p.err != nil {
+		return Errors{p.err}
+	}
+
+	if len(p.errs) > 0 {
+		return p.errs
+	}
+
+	if err := opts.limits.checkFields(sField, queryValue, p.conditions); err != nil {
+		return err
+	}
+
+	f.Conditions, f.Root = p.conditions, root
+
+	return nil
+}
+
+// fail records the first syntax error of code, parsing stops once
+// it is set. Token is the unexpected or expected token, if any
+func (p *exprParser) fail(code ErrorCode, token string) {
+	if p.err != nil {
+		return
+	}
+
+	p.err = newFieldError(code, p.sField.Name, p.input, map[string]string{
+		"position": strconv.Itoa(p.pos),
+		"token":    token,
+	})
+}
+
+// exceed records the first error as a LimitError of code
+func (p *exprParser) exceed(limit string, max int, code ErrorCode) {
+	if p.err != nil {
+		return
+	}
+
+	p.err = limitError(p.sField, p.input, limit, max, code, map[string]string{
+		"position": strconv.Itoa(p.pos),
+	})
+}
+
+func (p *exprParser) next(token string) bool {
+	if strings.HasPrefix(p.input[p.pos:], token) {
+		p.pos += len(token)
+		return true
+	}
+
+	return false
+}
+
+func (p *exprParser) count() {
+	p.nodes++
+
+	if p.nodes > p.opts.expr.maxNodes {
+		p.exceed("maxnodes", p.opts.expr.maxNodes, CodeMaxNodes)
+	}
+}
+
+func (p *exprParser) parseOr() Node {
+	nodes := Or{p.parseAnd()}
+
+	for p.err == nil && p.next(p.opts.expr.or) {
+		nodes = append(nodes, p.parseAnd())
+	}
+
+	if len(nodes) == 1 {
+		return nodes[0]
+	}
+
+	p.count()
+
+	return nodes
+}
+
+func (p *exprParser) parseAnd() Node {
+	nodes := And{p.parseFactor()}
+
+	for p.err == nil && p.next(p.opts.sep) {
+		nodes = append(nodes, p.parseFactor())
+	}
+
+	if len(nodes) == 1 {
+		return nodes[0]
+	}
+
+	p.count()
+
+	return nodes
+}
+
+func (p *exprParser) parseFactor() Node {
+	for _, t := range p.opts.not {
+		str := p.input[p.pos:]
+		if rest, ok := cutNot(str, t); ok && p.startsGroup(rest) {
+			p.pos += len(str) - len(rest)
+			p.count()
+
+			return Not{p.parseFactor()}
+		}
+	}
+
+	if p.next(p.opts.expr.open) {
+		p.depth++
+		if p.depth > p.opts.expr.maxDepth {
+			p.exceed("maxdepth", p.opts.expr.maxDepth, CodeMaxDepth)
+			return nil
+		}
+
+		n := p.parseOr()
+
+		if p.err == nil && !p.next(p.opts.expr.close) {
+			p.fail(CodeExprUnclosedGroup, p.opts.expr.close)
+		}
+
+		p.depth--
+
+		return n
+	}
+
+	return p.parseCondition()
+}
+
+// startsGroup reports whether str starts with a, possibly
+// negated, group
+func (p *exprParser) startsGroup(str string) bool {
+	if strings.HasPrefix(str, p.opts.expr.open) {
+		return true
+	}
+
+	for _, t := range p.opts.not {
+		if rest, ok := cutNot(str, t); ok && p.startsGroup(rest) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseCondition consumes input up to the next separator, or token
+// or closing group outside of parentheses (eg. set values)
+func (p *exprParser) parseCondition() Node {
+	if max := p.opts.limits.MaxConditions; max > 0 && len(p.conditions) >= max {
+		p.exceed("maxconditions", max, CodeMaxConditions)
+		return nil
+	}
+
+	start, depth := p.pos, 0
+
+scan:
+	for ; p.pos < len(p.input); p.pos++ {
+		rest := p.input[p.pos:]
+
+		switch {
+		case rest[0] == '(':
+			depth++
+		case rest[0] == ')' && depth > 0:
+			depth--
+		case depth > 0:
+		case strings.HasPrefix(rest, p.opts.expr.close),
+			strings.HasPrefix(rest, p.opts.sep),
+			strings.HasPrefix(rest, p.opts.expr.or):
+			break scan
+		}
+	}
+
+	if depth > 0 {
+		p.fail(CodeExprUnclosedGroup, ")")
+		return nil
+	}
+
+	raw := p.input[start:p.pos]
+	if raw == "" {
+		p.fail(CodeExprMissingCondition, "")
+		return nil
+	}
+
+	p.count()
+
+	c, err := p.opts.parseCondition(p.sField, raw)
+	if err != nil {
+		p.errs = append(p.errs, err)
+	}
+
+	p.conditions = append(p.conditions, c)
+
+	return c
+}
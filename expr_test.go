@@ -0,0 +1,198 @@
+package qparams
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestParseFilterExpr(t *testing.T) {
+	type testStruct struct {
+		Filter Filter `qparams:"expr:true inop:=in= types:amount=int maxdepth:2 maxnodes:6"`
+	}
+
+	paid := Condition{Field: "status", Op: Eq, RawOp: "==", Value: "paid"}
+	refunded := Condition{Field: "status", Op: Eq, RawOp: "==", Value: "refunded"}
+	amount := Condition{Field: "amount", Op: Gte, RawOp: ">=", Value: "100", Typed: 100}
+
+	table := []testCase{
+		{
+			URL: "foobar.com?filter=(status==paid|status==refunded),amount>=100",
+			ExpectedResult: testStruct{Filter: Filter{
+				Conditions: []Condition{paid, refunded, amount},
+				Root:       And{Or{paid, refunded}, amount},
+			}},
+			ExpectedError: nil,
+		},
+
+		{
+			URL: "foobar.com?filter=status==paid,amount>=100|status==refunded",
+			ExpectedResult: testStruct{Filter: Filter{
+				Conditions: []Condition{paid, amount, refunded},
+				Root:       Or{And{paid, amount}, refunded},
+			}},
+			ExpectedError: nil,
+		},
+
+		{
+			URL: "foobar.com?filter=status=in=(paid,refunded)",
+			ExpectedResult: testStruct{Filter: Filter{
+				Conditions: []Condition{{Field: "status", Op: In, RawOp: "=in=", Value: "(paid,refunded)", Values: []string{"paid", "refunded"}}},
+				Root:       Condition{Field: "status", Op: In, RawOp: "=in=", Value: "(paid,refunded)", Values: []string{"paid", "refunded"}},
+			}},
+			ExpectedError: nil,
+		},
+
+		{
+			URL:            "foobar.com?filter=(status==paid|status==refunded,amount>=100",
+			ExpectedResult: testStruct{},
+			ExpectedError: TypeConvErrors{
+				"Field Filter contains invalid filter expression at position 42, expected ) ((status==paid|status==refunded,amount>=100)",
+			},
+		},
+
+		{
+			URL:            "foobar.com?filter=status==paid,,amount>=100",
+			ExpectedResult: testStruct{},
+			ExpectedError: TypeConvErrors{
+				"Field Filter contains invalid filter expression at position 13, expected condition (status==paid,,amount>=100)",
+			},
+		},
+
+		{
+			URL:            "foobar.com?filter=(((a==1)))",
+			ExpectedResult: testStruct{},
+			ExpectedError: TypeConvErrors{
+				"Field Filter nests groups deeper than 2",
+			},
+		},
+
+		{
+			URL:            "foobar.com?filter=a==1|a==2|a==3|a==4|a==5|a==6",
+			ExpectedResult: testStruct{},
+			ExpectedError: TypeConvErrors{
+				"Field Filter contains more than 6 expression nodes",
+			},
+		},
+
+		{
+			URL:            "foobar.com?filter=name==Smith%20(Jr,amount>=3",
+			ExpectedResult: testStruct{},
+			ExpectedError: TypeConvErrors{
+				"Field Filter contains invalid filter expression at position 25, expected ) (name==Smith (Jr,amount>=3)",
+			},
+		},
+
+		{
+			URL:            "foobar.com?filter=a==1)",
+			ExpectedResult: testStruct{},
+			ExpectedError: TypeConvErrors{
+				"Field Filter contains invalid filter expression at position 4, unexpected ) (a==1))",
+			},
+		},
+
+		{
+			URL:            "foobar.com?filter=(a==1|amount>=x)",
+			ExpectedResult: testStruct{},
+			ExpectedError: TypeConvErrors{
+				"Filter field amount does not contain a valid int (x)",
+			},
+		},
+	}
+
+	t.Log("")
+	t.Log("Testing filter expression parsing")
+
+	for _, c := range table {
+		opts := testStruct{}
+		r := newRequest(c.URL)
+		err := Parse(&opts, r)
+
+		compare(t, c, opts, err)
+	}
+}
+
+func TestFilterExpr(t *testing.T) {
+	type testStruct struct {
+		Filter Filter
+	}
+
+	opts := testStruct{}
+	Parse(&opts, newRequest("foobar.com?filter=a==1,b>2"))
+
+	want := And{
+		Condition{Field: "a", Op: Eq, RawOp: "==", Value: "1"},
+		Condition{Field: "b", Op: Gt, RawOp: ">", Value: "2"},
+	}
+
+	got := opts.Filter.Expr()
+
+	compare(t, testCase{ExpectedResult: want}, got, nil)
+}
+
+func TestParseFilterExprNot(t *testing.T) {
+	type testStruct struct {
+		Filter Filter `qparams:"expr:true not:!,not~,not isnull:=isnull"`
+	}
+
+	paid := Condition{Field: "status", Op: Eq, RawOp: "==", Value: "paid"}
+	deleted := Condition{Field: "deleted_at", Op: IsNull, RawOp: "=isnull", Not: true}
+
+	table := []testCase{
+		{
+			URL: "foobar.com?filter=not~(status==paid|deleted_at=isnull),!deleted_at=isnull",
+			ExpectedResult: testStruct{Filter: Filter{
+				Conditions: []Condition{paid, {Field: "deleted_at", Op: IsNull, RawOp: "=isnull"}, deleted},
+				Root:       And{Not{Or{paid, Condition{Field: "deleted_at", Op: IsNull, RawOp: "=isnull"}}}, deleted},
+			}},
+			ExpectedError: nil,
+		},
+
+		{
+			URL: "foobar.com?filter=not%20(status==paid),notes==x",
+			ExpectedResult: testStruct{Filter: Filter{
+				Conditions: []Condition{paid, {Field: "notes", Op: Eq, RawOp: "==", Value: "x"}},
+				Root:       And{Not{paid}, Condition{Field: "notes", Op: Eq, RawOp: "==", Value: "x"}},
+			}},
+			ExpectedError: nil,
+		},
+
+		{
+			URL: "foobar.com?filter=!!(status==paid)",
+			ExpectedResult: testStruct{Filter: Filter{
+				Conditions: []Condition{paid},
+				Root:       Not{Not{paid}},
+			}},
+			ExpectedError: nil,
+		},
+	}
+
+	t.Log("")
+	t.Log("Testing filter expression negation")
+
+	for _, c := range table {
+		opts := testStruct{}
+		r := newRequest(c.URL)
+		err := Parse(&opts, r)
+
+		compare(t, c, opts, err)
+	}
+}
+
+func TestParseSemicolonQuery(t *testing.T) {
+	type testStruct struct {
+		Filter Filter `qparams:"expr:true"`
+	}
+
+	var opts testStruct
+
+	r, _ := http.NewRequest("GET", "http://foobar.com/?filter=(status==paid;status==refunded),amount>=100", nil)
+	if err := Parse(&opts, r); !errors.Is(err, ErrSemicolonQuery) {
+		t.Fatalf("Incorrect error WANT: %v GOT: %v", ErrSemicolonQuery, err)
+	}
+
+	r, _ = http.NewRequest("GET", "http://foobar.com/?filter=(status==paid|status==refunded),amount>=100", nil)
+	if err := Parse(&opts, r); err != nil || len(opts.Filter.Conditions) != 3 {
+		t.Fatalf("Expected 3 conditions GOT: %v %v", opts.Filter, err)
+	}
+}
@@ -0,0 +1,176 @@
+package qparams
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// FieldMapper resolves public filter and sort field names eg. createdAt
+// to storage names eg. orders.created_at, so that storage schemas are
+// not exposed through query params. Field names are lowercased by
+// the parser, ok is false for unmapped fields which are rejected
+type FieldMapper interface {
+	MapField(field string) (name string, ok bool)
+}
+
+// FieldMap maps public field names to storage names,
+// names are matched case-insensitively
+type FieldMap map[string]string
+
+// MapField implements FieldMapper
+func (m FieldMap) MapField(field string) (string, bool) {
+	if name, ok := m[field]; ok {
+		return name, true
+	}
+
+	for k, name := range m {
+		if strings.EqualFold(k, field) {
+			return name, true
+		}
+	}
+
+	return "", false
+}
+
+// FieldMapFunc adapts resolver func to FieldMapper
+type FieldMapFunc func(field string) (string, bool)
+
+// MapField implements FieldMapper
+func (f FieldMapFunc) MapField(field string) (string, bool) {
+	return f(field)
+}
+
+// FieldMapOf builds FieldMap from fields of struct type T, public
+// names are lowercased field names or set with name tag and storage
+// names are read from tag key eg. db, bson or json
+//
+//	type Order struct {
+//		Created time.Time `qparams:"name:createdat" db:"orders.created_at"`
+//	}
+//
+// Fields without the tag, or with - value, are not mapped
+func FieldMapOf[T any](key string) (FieldMap, error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("Field map type %s must be a struct", t)
+	}
+
+	m := FieldMap{}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get(key), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		public := strings.ToLower(field.Name)
+		if tagName := getTag("name", field); tagName != "" {
+			public = tagName
+		}
+
+		m[public] = name
+	}
+
+	return m, nil
+}
+
+// Map returns copy of q with filter and sort fields
+// mapped by m, unmapped fields yield ErrUnknownField
+func (q Query) Map(m FieldMapper) (Query, error) {
+	var err error
+
+	q.Filter, err = q.Filter.Map(m)
+	if err != nil {
+		return Query{}, err
+	}
+
+	if q.Sort != nil {
+		sort := make(Sort, 0, len(q.Sort))
+
+		for _, s := range q.Sort {
+			if s.Field, err = mapField(m, s.Field); err != nil {
+				return Query{}, err
+			}
+
+			sort = append(sort, s)
+		}
+
+		q.Sort = sort
+	}
+
+	return q, nil
+}
+
+// Map returns copy of f with condition fields mapped by m,
+// unmapped fields yield ErrUnknownField
+func (f Filter) Map(m FieldMapper) (Filter, error) {
+	mapped := Filter{}
+
+	for _, c := range f.Conditions {
+		var err error
+		if c.Field, err = mapField(m, c.Field); err != nil {
+			return Filter{}, err
+		}
+
+		mapped.Conditions = append(mapped.Conditions, c)
+	}
+
+	if f.Root != nil {
+		var err error
+		if mapped.Root, err = mapNode(m, f.Root); err != nil {
+			return Filter{}, err
+		}
+	}
+
+	return mapped, nil
+}
+
+func mapField(m FieldMapper, field string) (string, error) {
+	name, ok := m.MapField(field)
+	if !ok {
+		return "", fmt.Errorf("%w %s", ErrUnknownField, field)
+	}
+
+	return name, nil
+}
+
+func mapNode(m FieldMapper, n Node) (Node, error) {
+	var nodes []Node
+
+	switch n := n.(type) {
+	case And:
+		nodes = n
+	case Or:
+		nodes = n
+	case Not:
+		node, err := mapNode(m, n.Node)
+		return Not{node}, err
+	case Condition:
+		var err error
+		n.Field, err = mapField(m, n.Field)
+		return n, err
+	}
+
+	mapped := make([]Node, 0, len(nodes))
+
+	for _, node := range nodes {
+		node, err := mapNode(m, node)
+		if err != nil {
+			return nil, err
+		}
+
+		mapped = append(mapped, node)
+	}
+
+	if _, ok := n.(Or); ok {
+		return Or(mapped), nil
+	}
+
+	return And(mapped), nil
+}
@@ -0,0 +1,142 @@
+package qparams
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFieldMapOf(t *testing.T) {
+	type order struct {
+		ID      int
+		Created time.Time `qparams:"name:createdat" db:"orders.created_at"`
+		Name    string    `db:"c.full_name,omitempty"`
+		Secret  string    `db:"-"`
+		Status  string
+	}
+
+	m, err := FieldMapOf[order]("db")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := FieldMap{"createdat": "orders.created_at", "name": "c.full_name"}
+	if !reflect.DeepEqual(m, want) {
+		t.Fatalf("Incorrect field map WANT: %v GOT: %v", want, m)
+	}
+
+	if _, err := FieldMapOf[int]("db"); err == nil {
+		t.Fatalf("Expected error for non struct type")
+	}
+}
+
+func TestQueryMap(t *testing.T) {
+	created := Condition{Field: "createdat", Op: Gte, RawOp: ">=", Value: "2024-01-01"}
+	name := Condition{Field: "customer.name", Op: Eq, RawOp: "==", Value: "jo"}
+	status := Condition{Field: "status", Op: Eq, RawOp: "==", Value: "paid"}
+
+	q := Query{
+		Filter: Filter{
+			Conditions: []Condition{created, name, status},
+			Root:       And{created, Or{name, Not{status}}},
+		},
+		Sort: Sort{{Field: "createdat", Desc: true}},
+	}
+
+	m := FieldMap{"createdAt": "orders.created_at", "customer.name": "c.full_name", "status": "o.status"}
+
+	mapped, err := q.Map(m)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := And{
+		Condition{Field: "orders.created_at", Op: Gte, RawOp: ">=", Value: "2024-01-01"},
+		Or{
+			Condition{Field: "c.full_name", Op: Eq, RawOp: "==", Value: "jo"},
+			Not{Condition{Field: "o.status", Op: Eq, RawOp: "==", Value: "paid"}},
+		},
+	}
+
+	if !reflect.DeepEqual(mapped.Filter.Root, want) {
+		t.Fatalf("Incorrect expression WANT: %#v GOT: %#v", want, mapped.Filter.Root)
+	}
+
+	if len(mapped.Filter.Conditions) != 3 || mapped.Filter.Conditions[2].Field != "o.status" {
+		t.Fatalf("Incorrect conditions GOT: %#v", mapped.Filter.Conditions)
+	}
+
+	if !reflect.DeepEqual(mapped.Sort, Sort{{Field: "orders.created_at", Desc: true}}) {
+		t.Fatalf("Incorrect sort GOT: %v", mapped.Sort)
+	}
+
+	if q.Filter.Conditions[0].Field != "createdat" || q.Sort[0].Field != "createdat" {
+		t.Fatalf("Original query was modified")
+	}
+
+	if _, err := q.Map(FieldMap{"createdat": "created"}); !errors.Is(err, ErrUnknownField) {
+		t.Fatalf("Incorrect error WANT: %v GOT: %v", ErrUnknownField, err)
+	}
+}
+
+func TestApplyMapped(t *testing.T) {
+	m := FieldMapFunc(func(field string) (string, bool) {
+		if field == "total" {
+			return "Amount", true
+		}
+		return "", false
+	})
+
+	q, err := Query{Filter: Filter{Root: cond("total", Gte, "50")}, Sort: Sort{{Field: "total", Desc: true}}}.Map(m)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	orders, err := Apply(evalOrders(), q)
+	if err != nil || len(orders) != 2 || orders[0].ID != 2 || orders[1].ID != 4 {
+		t.Fatalf("Incorrect result WANT: [2 4] GOT: %v %v", orders, err)
+	}
+
+	_, err = where(cond("amount", Gte, "50")).Map(m)
+	if err == nil || !strings.Contains(err.Error(), "amount") {
+		t.Fatalf("Expected unknown field error GOT: %v", err)
+	}
+}
+
+func TestApplyMapper(t *testing.T) {
+	type order struct {
+		Created time.Time `qparams:"name:createdat" db:"orders.created_at"`
+		Name    string    `db:"c.full_name"`
+	}
+
+	orders := []order{
+		{Created: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Name: "John"},
+		{Created: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), Name: "Jane"},
+		{Created: time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC), Name: "Ann"},
+	}
+
+	m := FieldMap{"createdat": "Created", "name": "Name"}
+
+	query := where(And{cond("createdat", Gte, "2024-01-01T00:00:00Z"), cond("name", Like, "J*")})
+	query.Sort = Sort{{Field: "createdat", Desc: true}}
+
+	page, err := Apply(orders, query, m)
+	if err != nil || len(page) != 2 || page[0].Name != "Jane" || page[1].Name != "John" {
+		t.Fatalf("Incorrect result WANT: [Jane John] GOT: %v %v", page, err)
+	}
+
+	ok, err := Match(query.Filter, orders[2], m)
+	if err != nil || ok {
+		t.Fatalf("Expected no match GOT: %v %v", ok, err)
+	}
+
+	if _, err := Apply(orders, where(cond("customer", Eq, "John")), m); !errors.Is(err, ErrUnknownField) {
+		t.Fatalf("Incorrect error WANT: %v GOT: %v", ErrUnknownField, err)
+	}
+
+	if _, err := Match(Filter{Root: cond("secret", Eq, "x")}, orders[0], m); !errors.Is(err, ErrUnknownField) {
+		t.Fatalf("Incorrect error WANT: %v GOT: %v", ErrUnknownField, err)
+	}
+}
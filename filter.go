@@ -0,0 +1,757 @@
+package qparams
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Filter represents a parsed filter query param eg.
+// filter=amount>=1000,currency==EUR
+//
+// Unlike Map it keeps every condition in order and can convert
+// condition values to types declared with the types tag eg.
+// `qparams:"ops:>=,== types:amount=decimal,created=time(2006-01-02)"`
+//
+// Supported types are string, int, float, decimal, bool,
+// time(layout) and enum(a|b|c)
+//
+// Condition operators are resolved to canonical Op using
+// DefaultAliases, Decoder.Aliases and alias tag eg.
+// `qparams:"ops:=ge=,~ alias:~=contains"`
+//
+// Set operators are declared with inop and notin tags eg.
+// `qparams:"inop:=in=,@ notin:=out= listsep:|"` so that
+// status=in=(paid|refunded) or status@paid|refunded yield a
+// condition with Values, the parentheses allow the list separator
+// to be the same as the filter separator
+//
+// Range operators are declared with range tag eg. `qparams:"range:="`
+// so that amount=10..100, amount=10.. or amount=..100 yield a
+// condition with Range
+//
+// Null check operators, which take no value, are declared with
+// isnull and notnull tags eg. `qparams:"isnull:=isnull notnull:=notnull"`
+// so that deleted_at=isnull yields a condition with IsNull Op.
+// Conditions are negated with ! prefix (set with not tag) eg.
+// !status==paid
+//
+// Pattern matching operators are declared with contains,
+// startswith, endswith and like tags, and their case-insensitive
+// variants icontains, istartswith, iendswith and ilike eg.
+// `qparams:"contains:=contains= like:== ilike:=ilike="` so that
+// name=contains=jo or name==Jo* yield a condition with Pattern.
+// Like values use * wildcard (\* and \\ are literal * and \),
+// like operators also listed in ops tag are only pattern
+// conditions if their value has a wildcard, otherwise they are
+// Eq conditions with unescaped value eg. name==Jo\* yields Jo*
+//
+// Boolean expressions are enabled with expr tag eg.
+// `qparams:"expr:true"` so that
+// filter=(status==paid|status==refunded),amount>=100 is parsed into
+// Root expression tree. Filter separator is the AND token and binds
+// tighter than the OR token (| by default, set with or tag),
+// parentheses group (set with group tag eg. group:[]) and negated
+// groups eg. !(a==1|b==2) yield Not node.
+// Nesting depth and number of nodes are limited with maxdepth and
+// maxnodes tags (8 and 100 by default), see Limits for other limits
+type Filter struct {
+	// Conditions holds all conditions in order of appearance
+	Conditions []Condition
+
+	// Root is the parsed expression tree, it is only set
+	// if expressions are enabled
+	Root Node
+}
+
+// Condition represents a single filter condition eg. amount>=1000
+type Condition struct {
+	// Field is the lowercased name of the filtered field
+	Field string
+
+	// Op is the canonical operator, Custom if the operator
+	// has no alias
+	Op Op
+
+	// RawOp is the operator as written in the query
+	RawOp string
+
+	// Value is the raw condition value
+	Value string
+
+	// Values holds the members of a set condition
+	// eg. status=in=(paid,refunded), it is nil for other conditions
+	Values []string
+
+	// Range holds the bounds of a range condition eg. amount=10..100,
+	// it is nil for other conditions
+	Range *Bounds
+
+	// Pattern holds the pattern of a pattern matching condition
+	// eg. name=contains=jo or name==Jo*, it is nil for other conditions
+	Pattern *Pattern
+
+	// Not reports whether the condition is negated eg. NOT IN,
+	// not null or a condition with negation prefix eg. !status==paid
+	Not bool
+
+	// Typed holds Value converted to the type declared for Field,
+	// a slice of converted Values for set conditions, or a
+	// [2]interface{} of converted lower and upper bound (nil if open)
+	// for range conditions. It is nil if no type is declared
+	Typed interface{}
+}
+
+// FilterOf is a Filter whose filterable fields and their types are
+// described by struct type T eg.
+//
+//	type OrderFilter struct {
+//		Amount  qparams.Decimal
+//		Created time.Time `qparams:"layout:2006-01-02"`
+//		Status  string    `qparams:"enum:paid|refunded"`
+//	}
+//
+// Conditions on fields not present in T are rejected
+type FilterOf[T any] struct {
+	Filter
+}
+
+// defaultFilterOperators are used when Filter field has no ops tag
+var defaultFilterOperators = []string{"==", "!=", ">=", "<=", ">", "<"}
+
+// defaultNotToken is the default condition negation prefix
+const defaultNotToken = "!"
+
+// Expr returns filter expression tree, for filters without
+// expressions enabled it is an And of all conditions
+func (f *Filter) Expr() Node {
+	if f.Root != nil {
+		return f.Root
+	}
+
+	and := And{}
+	for _, c := range f.Conditions {
+		and = append(and, c)
+	}
+
+	return and
+}
+
+// Get returns the first condition for field and op
+func (f *Filter) Get(field string, op Op) (Condition, bool) {
+	for _, c := range f.Conditions {
+		if c.Field == field && c.Op == op {
+			return c, true
+		}
+	}
+
+	return Condition{}, false
+}
+
+func (f *Filter) parseField(d *Decoder, sField reflect.StructField, queryValue string) error {
+	opts, err := d.filterOptions(sField)
+	if err != nil {
+		return err
+	}
+
+	return f.parse(sField, queryValue, opts)
+}
+
+func (f *FilterOf[T]) parseField(d *Decoder, sField reflect.StructField, queryValue string) error {
+	opts, err := d.filterOptions(sField)
+	if err != nil {
+		return err
+	}
+
+	return f.Filter.parse(sField, queryValue, opts)
+}
+
+// filterOptions holds filter parsing options read from field tags
+type filterOptions struct {
+	sep       string
+	listSep   string
+	operators []string
+
+	// aliases maps operator tokens to canonical operators
+	aliases map[string]Op
+
+	// plainOps holds operators listed in ops tag
+	plainOps map[string]bool
+
+	// not lists negation prefixes
+	not []string
+
+	types  map[string]valueType
+	strict bool
+
+	expr   exprOptions
+	limits Limits
+}
+
+// filterTags holds filter options read from field tags, they are
+// built and validated once per field by getPlan and shared by all
+// decoders, which add their aliases and limits
+type filterTags struct {
+	// opts are options of decoders without aliases
+	opts filterOptions
+
+	// operators are listed in ops tag
+	operators []string
+
+	// aliases are set with alias and operator tags eg. inop
+	aliases map[string]Op
+
+	// aliasOps lists tokens of aliases
+	aliasOps []string
+
+	// limits are set with limit tags
+	limits map[string]int
+}
+
+// filterTagsKey identifies filter fields sharing filterTags
+type filterTagsKey struct {
+	t    reflect.Type
+	name string
+	tag  reflect.StructTag
+}
+
+var (
+	filterTagsCache sync.Map
+
+	filterFieldType = reflect.TypeOf((*filterField)(nil)).Elem()
+)
+
+// filterField is implemented by Filter and FilterOf
+type filterField interface {
+	// schemaTypes returns value types of FilterOf schema,
+	// strict is false for untyped Filter
+	schemaTypes() (types map[string]valueType, strict bool, err error)
+}
+
+func (f *Filter) schemaTypes() (map[string]valueType, bool, error) {
+	return nil, false, nil
+}
+
+func (f *FilterOf[T]) schemaTypes() (map[string]valueType, bool, error) {
+	types, err := structValueTypes(reflect.TypeOf((*T)(nil)).Elem())
+	return types, true, err
+}
+
+// getFilterTags returns cached filter tags of sField,
+// building them on first use
+func getFilterTags(sField reflect.StructField) (*filterTags, error) {
+	key := filterTagsKey{t: sField.Type, name: sField.Name, tag: sField.Tag}
+
+	if ft, ok := filterTagsCache.Load(key); ok {
+		return ft.(*filterTags), nil
+	}
+
+	ft, err := newFilterTags(sField)
+	if err != nil {
+		return nil, err
+	}
+
+	actual, _ := filterTagsCache.LoadOrStore(key, ft)
+
+	return actual.(*filterTags), nil
+}
+
+func newFilterTags(sField reflect.StructField) (*filterTags, error) {
+	types, err := getValueTypes(sField)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, strict, err := reflect.New(sField.Type).Interface().(filterField).schemaTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	if strict {
+		types = schema
+	}
+
+	expr, err := getExprOptions(sField)
+	if err != nil {
+		return nil, err
+	}
+
+	aliases, err := getAliases(sField)
+	if err != nil {
+		return nil, err
+	}
+
+	limits, err := getLimitTags(sField)
+	if err != nil {
+		return nil, err
+	}
+
+	ft := &filterTags{
+		operators: getOperators(sField),
+		aliases:   aliases,
+		limits:    limits,
+	}
+
+	for o := range aliases {
+		ft.aliasOps = append(ft.aliasOps, o)
+	}
+
+	for tag, op := range opTags {
+		for _, o := range getTagList(tag, sField) {
+			ft.aliases[o] = op
+			ft.aliasOps = append(ft.aliasOps, o)
+		}
+	}
+
+	ft.opts = filterOptions{
+		sep:     getSeparator(sField),
+		listSep: separator,
+		not:     getTagList("not", sField),
+		types:   types,
+		strict:  strict,
+		expr:    expr,
+	}
+
+	if s := getTag("listsep", sField); s != "" {
+		ft.opts.listSep = s
+	}
+
+	if len(ft.opts.not) == 0 {
+		ft.opts.not = append(ft.opts.not, defaultNotToken)
+	}
+
+	ft.opts.operators, ft.opts.plainOps, ft.opts.aliases = ft.resolveOperators(nil)
+
+	return ft, nil
+}
+
+// resolveOperators returns operators, plain operators and aliases
+// of field extended with decoder aliases
+func (ft *filterTags) resolveOperators(decoderAliases map[string]Op) ([]string, map[string]bool, map[string]Op) {
+	operators := append([]string{}, ft.operators...)
+
+	if len(operators) == 0 {
+		operators = append(operators, defaultFilterOperators...)
+		for o := range decoderAliases {
+			operators = append(operators, o)
+		}
+	}
+
+	plainOps := make(map[string]bool)
+	for _, o := range operators {
+		plainOps[o] = true
+	}
+
+	aliases := make(map[string]Op)
+	for _, m := range []map[string]Op{DefaultAliases, decoderAliases, ft.aliases} {
+		for o, op := range m {
+			aliases[o] = op
+		}
+	}
+
+	return append(operators, ft.aliasOps...), plainOps, aliases
+}
+
+// filterOptions returns filter options of sField with decoder
+// aliases and limits
+func (d *Decoder) filterOptions(sField reflect.StructField) (filterOptions, error) {
+	ft, err := getFilterTags(sField)
+	if err != nil {
+		return filterOptions{}, err
+	}
+
+	opts := ft.opts
+
+	if len(d.Aliases) > 0 {
+		opts.operators, opts.plainOps, opts.aliases = ft.resolveOperators(d.Aliases)
+	}
+
+	opts.limits = d.Limits.override(ft.limits)
+
+	if opts.limits.MaxDepth > 0 {
+		opts.expr.maxDepth = opts.limits.MaxDepth
+	}
+
+	return opts, nil
+}
+
+func (f *Filter) parse(sField reflect.StructField, queryValue string, opts filterOptions) error {
+	f.Conditions = nil
+
+	if err := opts.limits.checkLength(sField, queryValue); err != nil {
+		return err
+	}
+
+	if opts.expr.enabled {
+		return f.parseExpr(sField, queryValue, opts)
+	}
+
+	var errs Errors
+
+	parts := strings.Split(queryValue, opts.sep)
+
+	if opts.hasSetOps() {
+		var ok bool
+		if parts, ok = splitTopLevel(queryValue, opts.sep); !ok {
+			return Errors{newFieldError(CodeInvalidCondition, sField.Name, queryValue, nil)}
+		}
+	}
+
+	var conditions []string
+	for _, raw := range parts {
+		if raw != "" {
+			conditions = append(conditions, raw)
+		}
+	}
+
+	if err := opts.limits.checkConditions(sField, queryValue, len(conditions)); err != nil {
+		return err
+	}
+
+	for _, raw := range conditions {
+		c, err := opts.parseCondition(sField, raw)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		f.Conditions = append(f.Conditions, c)
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	if err := opts.limits.checkFields(sField, queryValue, f.Conditions); err != nil {
+		f.Conditions = nil
+		return err
+	}
+
+	return nil
+}
+
+func (o filterOptions) parseCondition(sField reflect.StructField, raw string) (Condition, error) {
+	cond, not := o.stripNot(raw)
+
+	c, ok := splitCondition(cond, o.operators)
+	if !ok {
+		return Condition{}, newFieldError(CodeInvalidCondition, sField.Name, raw, nil)
+	}
+
+	if err := o.limits.checkValue(sField, c); err != nil {
+		return Condition{}, err
+	}
+
+	c.Op, c.Not = o.aliases[c.RawOp].normalize()
+
+	switch mode, fold, isMatch := c.Op.match(); {
+	case c.Op == In:
+		c.Values = splitList(c.Value, o.listSep)
+
+		if len(c.Values) == 0 {
+			return Condition{}, newFieldError(CodeFilterEmptySet, c.Field, c.Value, nil)
+		}
+	case c.Op == Between && strings.Contains(c.Value, rangeSeparator):
+		b, ok := splitRange(c.Value)
+		if !ok {
+			return Condition{}, newFieldError(CodeFilterInvalidRange, c.Field, c.Value, nil)
+		}
+
+		c.Range = &b
+	case c.Op == Between:
+		c.Op = Eq
+	case c.Op == IsNull:
+		if c.Value != "" {
+			return Condition{}, newFieldError(CodeFilterNullValue, c.Field, c.Value, nil)
+		}
+	case isMatch && mode == MatchLike && o.plainOps[c.RawOp] && !hasWildcard(c.Value):
+		// plain equality, escaped wildcards are literal text
+		c.Op, c.Value = Eq, unescapeLike(c.Value)
+	case isMatch:
+		if c.Value == "" {
+			return Condition{}, newFieldError(CodeFilterEmptyPattern, c.Field, c.Value, nil)
+		}
+
+		p := newPattern(mode, fold, c.Value)
+		c.Pattern = &p
+	}
+
+	c.Not = c.Not != not
+
+	vt, ok := o.types[c.Field]
+	if !ok || c.Op == IsNull || c.Pattern != nil {
+		if c.Range != nil && !boundsOrdered(c.Range.Lower, c.Range.Upper) {
+			return Condition{}, c.rangeError()
+		}
+
+		if !ok && o.strict {
+			return Condition{}, newFieldError(CodeFilterNotAllowed, c.Field, raw, nil)
+		}
+
+		return c, nil
+	}
+
+	if err := c.convert(vt); err != nil {
+		return Condition{}, err
+	}
+
+	return c, nil
+}
+
+// TypedValue returns Typed if set, raw Value otherwise
+func (c Condition) TypedValue() interface{} {
+	if c.Typed != nil {
+		return c.Typed
+	}
+
+	return c.Value
+}
+
+// TypedValues returns set condition members, typed if
+// field has a declared type
+func (c Condition) TypedValues() []interface{} {
+	var values []interface{}
+
+	if c.Typed != nil {
+		typed := reflect.ValueOf(c.Typed)
+		for i := 0; i < typed.Len(); i++ {
+			values = append(values, typed.Index(i).Interface())
+		}
+
+		return values
+	}
+
+	for _, v := range c.Values {
+		values = append(values, v)
+	}
+
+	return values
+}
+
+// TypedBounds returns range condition bounds, typed if field has
+// a declared type, nil for open bounds
+func (c Condition) TypedBounds() (lower, upper interface{}) {
+	if typed, ok := c.Typed.([2]interface{}); ok {
+		return typed[0], typed[1]
+	}
+
+	if c.Range == nil {
+		return nil, nil
+	}
+
+	if c.Range.Lower != "" {
+		lower = c.Range.Lower
+	}
+
+	if c.Range.Upper != "" {
+		upper = c.Range.Upper
+	}
+
+	return lower, upper
+}
+
+// hasSetOps reports whether set operators are declared, only
+// then parentheses enclose set values which may contain separators
+func (o filterOptions) hasSetOps() bool {
+	for _, op := range o.operators {
+		if canonical, _ := o.aliases[op].normalize(); canonical == In {
+			return true
+		}
+	}
+
+	return false
+}
+
+// stripNot strips negation prefixes from raw condition,
+// not reports whether the condition is negated
+func (o filterOptions) stripNot(raw string) (cond string, not bool) {
+	for {
+		stripped := false
+
+		for _, t := range o.not {
+			if rest, ok := cutNot(raw, t); ok {
+				raw, not, stripped = rest, !not, true
+				break
+			}
+		}
+
+		if !stripped {
+			return raw, not
+		}
+	}
+}
+
+// cutNot returns str without leading negation token t, ok is false
+// if str does not start with t. Tokens ending with a word character
+// (eg. not) must be followed by a non-word character, so they are
+// never found in field names eg. not status==paid but not notes==x.
+// Spaces following them are trimmed
+func cutNot(str, t string) (rest string, ok bool) {
+	if !strings.HasPrefix(str, t) {
+		return str, false
+	}
+
+	rest = str[len(t):]
+
+	if !isWordByte(t[len(t)-1]) {
+		return rest, true
+	}
+
+	if rest == "" || isWordByte(rest[0]) {
+		return str, false
+	}
+
+	return strings.TrimLeft(rest, " "), true
+}
+
+// convert sets Typed to the condition value, or the set
+// values, converted to vt
+func (c *Condition) convert(vt valueType) error {
+	if c.Range != nil {
+		lower, upper, err := c.Range.convert(vt)
+		if err != nil {
+			return c.convError(vt, c.Value)
+		}
+
+		if !boundsOrdered(lower, upper) {
+			return c.rangeError()
+		}
+
+		c.Typed = [2]interface{}{lower, upper}
+
+		return nil
+	}
+
+	if c.Values == nil {
+		typed, err := vt.convert(c.Value)
+		if err != nil {
+			return c.convError(vt, c.Value)
+		}
+
+		c.Typed = typed
+
+		return nil
+	}
+
+	var typed reflect.Value
+
+	for _, v := range c.Values {
+		t, err := vt.convert(v)
+		if err != nil {
+			return c.convError(vt, v)
+		}
+
+		if !typed.IsValid() {
+			typed = reflect.MakeSlice(reflect.SliceOf(reflect.TypeOf(t)), 0, len(c.Values))
+		}
+
+		typed = reflect.Append(typed, reflect.ValueOf(t))
+	}
+
+	c.Typed = typed.Interface()
+
+	return nil
+}
+
+func (c *Condition) convError(vt valueType, value string) error {
+	return newFieldError(CodeFilterInvalidValue, c.Field, value, map[string]string{"type": vt.String()})
+}
+
+func (c *Condition) rangeError() error {
+	return newFieldError(CodeFilterRangeOrder, c.Field, c.Value, nil)
+}
+
+// splitCondition splits raw condition on the leftmost operator,
+// preferring the longest operator at that position. Operators
+// starting with a word character (eg. gte) only match after a
+// non-word character, so they are never found inside field names
+// eg. age gte 5 but not agegte5. Spaces around them are trimmed
+func splitCondition(raw string, operators []string) (Condition, bool) {
+	for i := 0; i < len(raw); i++ {
+		op := ""
+
+		for _, o := range operators {
+			if len(o) > len(op) && strings.HasPrefix(raw[i:], o) && atWordBoundary(raw, i, o) {
+				op = o
+			}
+		}
+
+		if op == "" {
+			continue
+		}
+
+		field, value := raw[:i], raw[i+len(op):]
+
+		if isWordByte(op[0]) {
+			field = strings.TrimRight(field, " ")
+		}
+
+		if isWordByte(op[len(op)-1]) {
+			value = strings.TrimLeft(value, " ")
+		}
+
+		if field == "" {
+			return Condition{}, false
+		}
+
+		return Condition{
+			Field: strings.ToLower(field),
+			RawOp: op,
+			Value: value,
+		}, true
+	}
+
+	return Condition{}, false
+}
+
+// atWordBoundary reports whether operator o found at position i
+// of raw follows a non-word character, if o starts with a word
+// character
+func atWordBoundary(raw string, i int, o string) bool {
+	return !isWordByte(o[0]) || i > 0 && !isWordByte(raw[i-1])
+}
+
+// isWordByte reports whether c is a letter, digit or underscore
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// splitTopLevel splits str on separator, ignoring separators
+// enclosed in parentheses. ok is false if str has unclosed
+// parentheses, as they would enclose all following conditions
+func splitTopLevel(str, separator string) (parts []string, ok bool) {
+	depth, start := 0, 0
+
+	for i := 0; i < len(str); i++ {
+		switch {
+		case str[i] == '(':
+			depth++
+		case str[i] == ')' && depth > 0:
+			depth--
+		case depth == 0 && strings.HasPrefix(str[i:], separator):
+			parts = append(parts, str[start:i])
+			start = i + len(separator)
+			i += len(separator) - 1
+		}
+	}
+
+	return append(parts, str[start:]), depth == 0
+}
+
+// splitList splits set value eg. (paid|refunded) on separator,
+// skipping empty members
+func splitList(value, separator string) []string {
+	if strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")") {
+		value = value[1 : len(value)-1]
+	}
+
+	values := []string{}
+
+	for _, v := range strings.Split(value, separator) {
+		if v != "" {
+			values = append(values, v)
+		}
+	}
+
+	return values
+}
@@ -0,0 +1,335 @@
+package qparams
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseFilter(t *testing.T) {
+	type testStruct struct {
+		Filter Filter `qparams:"ops:>=,==,<"`
+	}
+
+	table := []testCase{
+		{
+			URL: "foobar.com?filter=Amount>=1000,currency==EUR",
+			ExpectedResult: testStruct{Filter: Filter{Conditions: []Condition{
+				{Field: "amount", Op: Gte, RawOp: ">=", Value: "1000"},
+				{Field: "currency", Op: Eq, RawOp: "==", Value: "EUR"},
+			}}},
+			ExpectedError: nil,
+		},
+
+		{
+			URL: "foobar.com?filter=,age<7,age>=3,",
+			ExpectedResult: testStruct{Filter: Filter{Conditions: []Condition{
+				{Field: "age", Op: Lt, RawOp: "<", Value: "7"},
+				{Field: "age", Op: Gte, RawOp: ">=", Value: "3"},
+			}}},
+			ExpectedError: nil,
+		},
+
+		{
+			URL: "foobar.com?filter=note==smile:(,age<5",
+			ExpectedResult: testStruct{Filter: Filter{Conditions: []Condition{
+				{Field: "note", Op: Eq, RawOp: "==", Value: "smile:("},
+				{Field: "age", Op: Lt, RawOp: "<", Value: "5"},
+			}}},
+			ExpectedError: nil,
+		},
+
+		{
+			URL:            "foobar.com?filter=age7,==3",
+			ExpectedResult: testStruct{},
+			ExpectedError: TypeConvErrors{
+				"Field Filter contains invalid filter condition (age7)",
+				"Field Filter contains invalid filter condition (==3)",
+			},
+		},
+	}
+
+	t.Log("")
+	t.Log("Testing filter parsing")
+
+	for _, c := range table {
+		opts := testStruct{}
+		r := newRequest(c.URL)
+		err := Parse(&opts, r)
+
+		compare(t, c, opts, err)
+	}
+}
+
+func TestParseTypedFilter(t *testing.T) {
+	type testStruct struct {
+		Filter Filter `qparams:"ops:>=,== types:amount=decimal,age=int,active=bool,created=time(2006-01-02),status=enum(paid|refunded)"`
+	}
+
+	table := []testCase{
+		{
+			URL: "foobar.com?filter=amount>=10.50,age==7,active==true,created>=2024-01-02,status==paid,name==john",
+			ExpectedResult: testStruct{Filter: Filter{Conditions: []Condition{
+				{Field: "amount", Op: Gte, RawOp: ">=", Value: "10.50", Typed: Decimal("10.50")},
+				{Field: "age", Op: Eq, RawOp: "==", Value: "7", Typed: 7},
+				{Field: "active", Op: Eq, RawOp: "==", Value: "true", Typed: true},
+				{Field: "created", Op: Gte, RawOp: ">=", Value: "2024-01-02", Typed: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+				{Field: "status", Op: Eq, RawOp: "==", Value: "paid", Typed: "paid"},
+				{Field: "name", Op: Eq, RawOp: "==", Value: "john"},
+			}}},
+			ExpectedError: nil,
+		},
+
+		{
+			URL: "foobar.com?filter=amount>=1e3,age==7,status==open",
+			ExpectedResult: testStruct{Filter: Filter{Conditions: []Condition{
+				{Field: "age", Op: Eq, RawOp: "==", Value: "7", Typed: 7},
+			}}},
+			ExpectedError: TypeConvErrors{
+				"Filter field amount does not contain a valid decimal (1e3)",
+				"Filter field status does not contain a valid value, one of paid, refunded (open)",
+			},
+		},
+	}
+
+	t.Log("")
+	t.Log("Testing typed filter parsing")
+
+	for _, c := range table {
+		opts := testStruct{}
+		r := newRequest(c.URL)
+		err := Parse(&opts, r)
+
+		compare(t, c, opts, err)
+	}
+}
+
+func TestParseFilterOf(t *testing.T) {
+	type orderFilter struct {
+		Amount  float64
+		Created time.Time `qparams:"layout:2006-01-02"`
+		Paid    bool      `qparams:"name:is_paid"`
+	}
+
+	type testStruct struct {
+		Filter FilterOf[orderFilter]
+	}
+
+	table := []testCase{
+		{
+			URL: "foobar.com?filter=amount>1.5,created<2024-01-02,is_paid==false",
+			ExpectedResult: testStruct{Filter: FilterOf[orderFilter]{Filter{Conditions: []Condition{
+				{Field: "amount", Op: Gt, RawOp: ">", Value: "1.5", Typed: 1.5},
+				{Field: "created", Op: Lt, RawOp: "<", Value: "2024-01-02", Typed: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+				{Field: "is_paid", Op: Eq, RawOp: "==", Value: "false", Typed: false},
+			}}}},
+			ExpectedError: nil,
+		},
+
+		{
+			URL:            "foobar.com?filter=secret==1,amount>x",
+			ExpectedResult: testStruct{},
+			ExpectedError: TypeConvErrors{
+				"Filter field secret is not filterable",
+				"Filter field amount does not contain a valid float (x)",
+			},
+		},
+	}
+
+	t.Log("")
+	t.Log("Testing filter parsing with struct schema")
+
+	for _, c := range table {
+		opts := testStruct{}
+		r := newRequest(c.URL)
+		err := Parse(&opts, r)
+
+		compare(t, c, opts, err)
+	}
+}
+
+func TestParseFilterSets(t *testing.T) {
+	type testStruct struct {
+		Filter Filter `qparams:"ops:>=,== inop:=in=,@ notin:=out= listsep:| types:age=int"`
+	}
+
+	table := []testCase{
+		{
+			URL: "foobar.com?filter=status=in=(paid|refunded),age>=18",
+			ExpectedResult: testStruct{Filter: Filter{Conditions: []Condition{
+				{Field: "status", Op: In, RawOp: "=in=", Value: "(paid|refunded)", Values: []string{"paid", "refunded"}},
+				{Field: "age", Op: Gte, RawOp: ">=", Value: "18", Typed: 18},
+			}}},
+			ExpectedError: nil,
+		},
+
+		{
+			URL: "foobar.com?filter=Status@paid|refunded,age=out=(1|2|)",
+			ExpectedResult: testStruct{Filter: Filter{Conditions: []Condition{
+				{Field: "status", Op: In, RawOp: "@", Value: "paid|refunded", Values: []string{"paid", "refunded"}},
+				{Field: "age", Op: In, RawOp: "=out=", Value: "(1|2|)", Values: []string{"1", "2"}, Not: true, Typed: []int{1, 2}},
+			}}},
+			ExpectedError: nil,
+		},
+
+		{
+			URL:            "foobar.com?filter=status=in=(),age@1|x",
+			ExpectedResult: testStruct{},
+			ExpectedError: TypeConvErrors{
+				"Filter field status contains an empty set (())",
+				"Filter field age does not contain a valid int (x)",
+			},
+		},
+	}
+
+	t.Log("")
+	t.Log("Testing filter set parsing")
+
+	for _, c := range table {
+		opts := testStruct{}
+		r := newRequest(c.URL)
+		err := Parse(&opts, r)
+
+		compare(t, c, opts, err)
+	}
+}
+
+func TestParseFilterSetsDefaultListSeparator(t *testing.T) {
+	type testStruct struct {
+		Filter Filter `qparams:"inop:=in="`
+	}
+
+	table := []testCase{
+		{
+			URL: "foobar.com?filter=status=in=(paid,refunded),amount>5",
+			ExpectedResult: testStruct{Filter: Filter{Conditions: []Condition{
+				{Field: "status", Op: In, RawOp: "=in=", Value: "(paid,refunded)", Values: []string{"paid", "refunded"}},
+				{Field: "amount", Op: Gt, RawOp: ">", Value: "5"},
+			}}},
+			ExpectedError: nil,
+		},
+
+		{
+			URL:            "foobar.com?filter=name==Smith%20(Jr,age>=3",
+			ExpectedResult: testStruct{},
+			ExpectedError:  TypeConvErrors{"Field Filter contains invalid filter condition (name==Smith (Jr,age>=3)"},
+		},
+	}
+
+	t.Log("")
+	t.Log("Testing filter set parsing with default list separator")
+
+	for _, c := range table {
+		opts := testStruct{}
+		r := newRequest(c.URL)
+		err := Parse(&opts, r)
+
+		compare(t, c, opts, err)
+	}
+}
+
+func TestParseFilterNull(t *testing.T) {
+	type testStruct struct {
+		Filter Filter `qparams:"isnull:=isnull,.null notnull:=notnull types:age=int"`
+	}
+
+	table := []testCase{
+		{
+			URL: "foobar.com?filter=deleted_at=isnull,age=notnull,!name.null,!age==7,!!age>1",
+			ExpectedResult: testStruct{Filter: Filter{Conditions: []Condition{
+				{Field: "deleted_at", Op: IsNull, RawOp: "=isnull"},
+				{Field: "age", Op: IsNull, RawOp: "=notnull", Not: true},
+				{Field: "name", Op: IsNull, RawOp: ".null", Not: true},
+				{Field: "age", Op: Eq, RawOp: "==", Value: "7", Not: true, Typed: 7},
+				{Field: "age", Op: Gt, RawOp: ">", Value: "1", Typed: 1},
+			}}},
+			ExpectedError: nil,
+		},
+
+		{
+			URL:            "foobar.com?filter=deleted_at=isnull1,!",
+			ExpectedResult: testStruct{},
+			ExpectedError: TypeConvErrors{
+				"Filter field deleted_at null check does not take a value (1)",
+				"Field Filter contains invalid filter condition (!)",
+			},
+		},
+	}
+
+	t.Log("")
+	t.Log("Testing filter null checks and negation")
+
+	for _, c := range table {
+		opts := testStruct{}
+		r := newRequest(c.URL)
+		err := Parse(&opts, r)
+
+		compare(t, c, opts, err)
+	}
+}
+
+func TestParseFilterWordNot(t *testing.T) {
+	type testStruct struct {
+		Filter Filter `qparams:"ops:==,gte not:!,not"`
+	}
+
+	table := []testCase{
+		{
+			URL: "foobar.com?filter=notes==x,nothing==1,not%20status==paid,not!age%20gte%203",
+			ExpectedResult: testStruct{Filter: Filter{Conditions: []Condition{
+				{Field: "notes", Op: Eq, RawOp: "==", Value: "x"},
+				{Field: "nothing", Op: Eq, RawOp: "==", Value: "1"},
+				{Field: "status", Op: Eq, RawOp: "==", Value: "paid", Not: true},
+				{Field: "age", Op: Gte, RawOp: "gte", Value: "3"},
+			}}},
+			ExpectedError: nil,
+		},
+
+		{
+			URL:            "foobar.com?filter=not",
+			ExpectedResult: testStruct{},
+			ExpectedError:  TypeConvErrors{"Field Filter contains invalid filter condition (not)"},
+		},
+	}
+
+	t.Log("")
+	t.Log("Testing filter word negation")
+
+	for _, c := range table {
+		opts := testStruct{}
+		r := newRequest(c.URL)
+		err := Parse(&opts, r)
+
+		compare(t, c, opts, err)
+	}
+}
+
+func TestParseSort(t *testing.T) {
+	type testStruct struct {
+		Sort Sort
+	}
+
+	table := []testCase{
+		{
+			URL:            "foobar.com?sort=-Created,name,+age,",
+			ExpectedResult: testStruct{Sort: Sort{{Field: "created", Desc: true}, {Field: "name"}, {Field: "age"}}},
+			ExpectedError:  nil,
+		},
+
+		{
+			URL:            "foobar.com?sort=name,-",
+			ExpectedResult: testStruct{Sort: Sort{{Field: "name"}}},
+			ExpectedError:  TypeConvErrors{"Field Sort contains invalid sort field (-)"},
+		},
+	}
+
+	t.Log("")
+	t.Log("Testing sort parsing")
+
+	for _, c := range table {
+		opts := testStruct{}
+		r := newRequest(c.URL)
+		err := Parse(&opts, r)
+
+		compare(t, c, opts, err)
+	}
+}
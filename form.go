@@ -0,0 +1,101 @@
+package qparams
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+)
+
+// Source selects request values read by Decoder.Parse
+type Source int
+
+// Supported sources
+const (
+	// SourceQuery reads URL query only, it is the default
+	SourceQuery Source = iota
+
+	// SourceForm reads form body only, url-encoded or multipart
+	SourceForm
+
+	// SourceFormFirst reads form body and URL query,
+	// form values take precedence
+	SourceFormFirst
+
+	// SourceQueryFirst reads URL query and form body,
+	// query values take precedence
+	SourceQueryFirst
+)
+
+// defaultMaxBodySize is the form body size limit if
+// Decoder.MaxBodySize is not set
+const defaultMaxBodySize = 10 << 20
+
+// requestValues returns request values of decoder source
+func (d *Decoder) requestValues(r *http.Request) (url.Values, error) {
+	query := r.URL.Query()
+
+	if d.Source == SourceQuery {
+		return query, nil
+	}
+
+	form, err := d.formValues(r)
+	if err != nil {
+		return nil, err
+	}
+
+	switch d.Source {
+	case SourceFormFirst:
+		return mergeValues(form, query), nil
+	case SourceQueryFirst:
+		return mergeValues(query, form), nil
+	}
+
+	return form, nil
+}
+
+// formValues parses url-encoded or multipart form body, reading
+// at most MaxBodySize bytes. Bodies parsed before are reused
+func (d *Decoder) formValues(r *http.Request) (url.Values, error) {
+	if r.PostForm == nil {
+		max := d.MaxBodySize
+		if max <= 0 {
+			max = defaultMaxBodySize
+		}
+
+		if r.Body != nil {
+			r.Body = http.MaxBytesReader(nil, r.Body, max)
+		}
+
+		var err error
+
+		ct, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if ct == "multipart/form-data" {
+			err = r.ParseMultipartForm(max)
+		} else {
+			err = r.ParseForm()
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("Could not parse form body: %w", err)
+		}
+	}
+
+	return r.PostForm, nil
+}
+
+// mergeValues returns values of first merged with keys
+// of second not present in first
+func mergeValues(first, second url.Values) url.Values {
+	merged := make(url.Values, len(first)+len(second))
+
+	for k, v := range second {
+		merged[k] = v
+	}
+
+	for k, v := range first {
+		merged[k] = v
+	}
+
+	return merged
+}
@@ -0,0 +1,90 @@
+package qparams
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func newFormRequest(url, body string) *http.Request {
+	r, _ := http.NewRequest("POST", url, strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return r
+}
+
+func newMultipartRequest(url string, fields map[string]string) *http.Request {
+	var body bytes.Buffer
+
+	w := multipart.NewWriter(&body)
+	for k, v := range fields {
+		w.WriteField(k, v)
+	}
+	w.Close()
+
+	r, _ := http.NewRequest("POST", url, &body)
+	r.Header.Set("Content-Type", w.FormDataContentType())
+	return r
+}
+
+func TestParseForm(t *testing.T) {
+	type testStruct struct {
+		Limit int
+		Page  int
+		Name  string
+	}
+
+	table := []struct {
+		source Source
+		r      *http.Request
+		want   testStruct
+	}{
+		{SourceQuery, newFormRequest("foobar.com?limit=1", "limit=2&page=3"), testStruct{Limit: 1}},
+		{SourceForm, newFormRequest("foobar.com?limit=1&name=q", "limit=2&page=3"), testStruct{Limit: 2, Page: 3}},
+		{SourceFormFirst, newFormRequest("foobar.com?limit=1&name=q", "limit=2&page=3"), testStruct{Limit: 2, Page: 3, Name: "q"}},
+		{SourceQueryFirst, newFormRequest("foobar.com?limit=1&name=q", "limit=2&page=3"), testStruct{Limit: 1, Page: 3, Name: "q"}},
+		{SourceFormFirst, newMultipartRequest("foobar.com?limit=1", map[string]string{"limit": "5", "name": "m"}), testStruct{Limit: 5, Name: "m"}},
+		{SourceForm, newRequest("foobar.com?limit=1"), testStruct{}},
+	}
+
+	for i, c := range table {
+		var got testStruct
+
+		d := &Decoder{Source: c.source}
+		if err := d.Parse(&got, c.r); err != nil {
+			t.Fatalf("Unexpected error for case %d: %v", i, err)
+		}
+
+		if got != c.want {
+			t.Fatalf("Incorrect result for case %d WANT: %+v GOT: %+v", i, c.want, got)
+		}
+	}
+}
+
+func TestParseFormBodyLimit(t *testing.T) {
+	type testStruct struct {
+		Name string
+	}
+
+	d := &Decoder{Source: SourceForm, MaxBodySize: 8}
+
+	var got testStruct
+
+	err := d.Parse(&got, newFormRequest("foobar.com", "name="+strings.Repeat("a", 20)))
+
+	var mbe *http.MaxBytesError
+	if !errors.As(err, &mbe) || mbe.Limit != 8 {
+		t.Fatalf("Incorrect error GOT: %v", err)
+	}
+
+	err = d.Parse(&got, newMultipartRequest("foobar.com", map[string]string{"name": strings.Repeat("a", 20)}))
+	if err == nil {
+		t.Fatalf("Expected body size error")
+	}
+
+	if err := d.Parse(&got, newFormRequest("foobar.com", "name=a")); err != nil || got.Name != "a" {
+		t.Fatalf("Incorrect result GOT: %+v %v", got, err)
+	}
+}
@@ -0,0 +1,75 @@
+package qparams
+
+import "net/http"
+
+// Validator is implemented by params validating themselves after
+// parsing, either by value or pointer receiver
+type Validator interface {
+	Validate() error
+}
+
+// ValidationError wraps the error returned by Validate
+type ValidationError struct {
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the error returned by Validate
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// Handler returns http.Handler parsing and validating request params
+// to T and calling fn with them, failures are written by TextRenderer.
+// Handler panics if T is not a struct
+//
+//	mux.Handle("GET /orders", qparams.Handler(func(w http.ResponseWriter, r *http.Request, p OrderParams) {
+//		// ...
+//	}))
+func Handler[T any](fn func(w http.ResponseWriter, r *http.Request, params T)) http.Handler {
+	return HandlerWith(Options{}, fn)
+}
+
+// HandlerWith is Handler using opts Decoder and Renderer
+func HandlerWith[T any](opts Options, fn func(w http.ResponseWriter, r *http.Request, params T)) http.Handler {
+	if err := Prepare[T](); err != nil {
+		panic(err)
+	}
+
+	d, renderer := opts.decoder(), opts.renderer()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		params, err := parseRequest[T](d, r)
+		if err != nil {
+			renderer.RenderError(w, r, err)
+			return
+		}
+
+		fn(w, r, params)
+	})
+}
+
+// parseRequest parses request params to T with d and validates
+// them if T implements Validator
+func parseRequest[T any](d *Decoder, r *http.Request) (T, error) {
+	params, err := ParseAsWith[T](d, r)
+	if err != nil {
+		return params, err
+	}
+
+	var v interface{} = &params
+	if _, ok := v.(Validator); !ok {
+		v = params
+	}
+
+	if v, ok := v.(Validator); ok {
+		if err := v.Validate(); err != nil {
+			return params, &ValidationError{Err: err}
+		}
+	}
+
+	return params, nil
+}
@@ -0,0 +1,111 @@
+package qparams
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+type handlerParams struct {
+	Limit int  `qparams:"default:10"`
+	Sort  Sort `qparams:"default:-created"`
+	Name  string
+}
+
+func (p *handlerParams) Validate() error {
+	if p.Limit > 100 {
+		return &FieldError{Param: "limit", Field: "Limit", Value: "", Reason: "Field Limit must not exceed 100"}
+	}
+
+	return nil
+}
+
+type valueValidatorParams struct {
+	Name string
+}
+
+func (p valueValidatorParams) Validate() error {
+	if p.Name == "" {
+		return errors.New("Name is required")
+	}
+
+	return nil
+}
+
+func TestHandler(t *testing.T) {
+	var got handlerParams
+
+	h := Handler(func(w http.ResponseWriter, r *http.Request, p handlerParams) {
+		got = p
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	table := []struct {
+		url    string
+		status int
+		body   string
+		want   handlerParams
+	}{
+		{"foobar.com?name=a", http.StatusNoContent, "", handlerParams{Limit: 10, Sort: Sort{{Field: "created", Desc: true}}, Name: "a"}},
+		{"foobar.com?limit=x", http.StatusBadRequest, "Field Limit does not contain a valid integer (x)\n", handlerParams{}},
+		{"foobar.com?limit=101", http.StatusBadRequest, "Field Limit must not exceed 100\n", handlerParams{}},
+	}
+
+	for i, c := range table {
+		got = handlerParams{}
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, newRequest(c.url))
+
+		if w.Code != c.status || w.Body.String() != c.body {
+			t.Fatalf("Incorrect response for case %d GOT: %d %q", i, w.Code, w.Body.String())
+		}
+
+		if !reflect.DeepEqual(got, c.want) {
+			t.Fatalf("Incorrect params for case %d GOT: %+v", i, got)
+		}
+	}
+}
+
+func TestHandlerWith(t *testing.T) {
+	var rendered error
+
+	opts := Options{
+		Renderer: ErrorRendererFunc(func(w http.ResponseWriter, r *http.Request, err error) {
+			rendered = err
+		}),
+	}
+
+	called := false
+
+	h := HandlerWith(opts, func(w http.ResponseWriter, r *http.Request, p valueValidatorParams) {
+		called = true
+	})
+
+	h.ServeHTTP(httptest.NewRecorder(), newRequest("foobar.com"))
+
+	var vErr *ValidationError
+	if called || !errors.As(rendered, &vErr) || vErr.Err.Error() != "Name is required" {
+		t.Fatalf("Incorrect validation error GOT: %v %v", called, rendered)
+	}
+
+	rendered = nil
+	h.ServeHTTP(httptest.NewRecorder(), newRequest("foobar.com?name=a"))
+
+	if !called || rendered != nil {
+		t.Fatalf("Handler should be called GOT: %v %v", called, rendered)
+	}
+}
+
+func TestMiddlewareValidation(t *testing.T) {
+	h := Middleware[handlerParams](Options{})(http.NotFoundHandler())
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newRequest("foobar.com?limit=500"))
+
+	if w.Code != http.StatusBadRequest || w.Body.String() != "Field Limit must not exceed 100\n" {
+		t.Fatalf("Incorrect response GOT: %d %q", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,205 @@
+package qparams
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Limits restrict the size and complexity of parsed filters, guarding
+// backends against expensive generated queries. Zero values mean no
+// limit. Limits are set on Decoder and overridden per field with
+// maxlength, maxconditions, maxvaluelen, maxfields and maxdepth tags
+// eg. `qparams:"maxconditions:10 maxvaluelen:64"`
+type Limits struct {
+	// MaxLength is the maximum length of the raw query param value
+	MaxLength int
+
+	// MaxConditions is the maximum number of conditions
+	MaxConditions int
+
+	// MaxValueLength is the maximum length of a condition value
+	MaxValueLength int
+
+	// MaxFields is the maximum number of distinct filtered fields
+	MaxFields int
+
+	// MaxDepth is the maximum nesting depth of grouped
+	// expressions, 8 if not set
+	MaxDepth int
+}
+
+// LimitError is returned when a query param exceeds one of its
+// Limits, it unwraps to the FieldError describing the failure
+type LimitError struct {
+	FieldError
+
+	// Limit is the tag name of the exceeded limit eg. maxconditions
+	Limit string
+
+	// Max is the value of the exceeded limit
+	Max int
+}
+
+// Unwrap returns the underlying FieldError
+func (e *LimitError) Unwrap() error {
+	return &e.FieldError
+}
+
+// fields returns limits by tag name
+func (l *Limits) fields() map[string]*int {
+	return map[string]*int{
+		"maxlength":     &l.MaxLength,
+		"maxconditions": &l.MaxConditions,
+		"maxvaluelen":   &l.MaxValueLength,
+		"maxfields":     &l.MaxFields,
+		"maxdepth":      &l.MaxDepth,
+	}
+}
+
+// override returns l with limits set by tags
+func (l Limits) override(tags map[string]int) Limits {
+	fields := l.fields()
+
+	for tag, n := range tags {
+		*fields[tag] = n
+	}
+
+	return l
+}
+
+// getLimitTags parses limit tags of sField
+func getLimitTags(sField reflect.StructField) (map[string]int, error) {
+	tags := make(map[string]int)
+
+	for tag := range (&Limits{}).fields() {
+		if v := getTag(tag, sField); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, fmt.Errorf("Field %s has invalid %s tag %s", sField.Name, tag, v)
+			}
+
+			tags[tag] = n
+		}
+	}
+
+	return tags, nil
+}
+
+// getLimits returns decoder limits overridden by sField tags
+func (d *Decoder) getLimits(sField reflect.StructField) (Limits, error) {
+	tags, err := getLimitTags(sField)
+	if err != nil {
+		return d.Limits, err
+	}
+
+	return d.Limits.override(tags), nil
+}
+
+func limitError(sField reflect.StructField, value, limit string, max int, code ErrorCode, args map[string]string) *LimitError {
+	if args == nil {
+		args = map[string]string{}
+	}
+
+	args["max"] = strconv.Itoa(max)
+
+	return &LimitError{
+		FieldError: *newFieldError(code, sField.Name, value, args),
+		Limit:      limit,
+		Max:        max,
+	}
+}
+
+// checkLength checks raw query param value length
+func (l Limits) checkLength(sField reflect.StructField, value string) error {
+	if l.MaxLength > 0 && len(value) > l.MaxLength {
+		return limitError(sField, value, "maxlength", l.MaxLength, CodeMaxLength, nil)
+	}
+
+	return nil
+}
+
+// checkConditions checks number of conditions n
+func (l Limits) checkConditions(sField reflect.StructField, value string, n int) error {
+	if l.MaxConditions > 0 && n > l.MaxConditions {
+		return limitError(sField, value, "maxconditions", l.MaxConditions, CodeMaxConditions, nil)
+	}
+
+	return nil
+}
+
+// checkValue checks condition value length
+func (l Limits) checkValue(sField reflect.StructField, c Condition) error {
+	if l.MaxValueLength > 0 && len(c.Value) > l.MaxValueLength {
+		return limitError(sField, c.Value, "maxvaluelen", l.MaxValueLength, CodeMaxValueLength,
+			map[string]string{"filter": c.Field})
+	}
+
+	return nil
+}
+
+// checkFields checks number of distinct condition fields
+func (l Limits) checkFields(sField reflect.StructField, value string, conditions []Condition) error {
+	if l.MaxFields <= 0 {
+		return nil
+	}
+
+	fields := make(map[string]bool)
+	for _, c := range conditions {
+		fields[c.Field] = true
+	}
+
+	if len(fields) > l.MaxFields {
+		return limitError(sField, value, "maxfields", l.MaxFields, CodeMaxFields, nil)
+	}
+
+	return nil
+}
+
+// checkMap checks Map and MultiMap param value before it is
+// parsed, non-empty conditions are counted the same way as
+// Filter conditions and walked for value length and distinct
+// fields limits
+func (d *Decoder) checkMap(sField reflect.StructField, value string) error {
+	l, err := d.getLimits(sField)
+	if err != nil {
+		return err
+	}
+
+	if err := l.checkLength(sField, value); err != nil {
+		return err
+	}
+
+	sep := getSeparator(sField)
+
+	var n int
+	for _, part := range strings.Split(value, sep) {
+		if part != "" {
+			n++
+		}
+	}
+
+	if err := l.checkConditions(sField, value, n); err != nil {
+		return err
+	}
+
+	if l.MaxValueLength <= 0 && l.MaxFields <= 0 {
+		return nil
+	}
+
+	var conditions []Condition
+
+	walkFunc(value, sep, getOperators(sField), func(key, v string) {
+		field, op := splitMapKey(key)
+		conditions = append(conditions, Condition{Field: field, RawOp: op, Value: v})
+	})
+
+	for _, c := range conditions {
+		if err := l.checkValue(sField, c); err != nil {
+			return err
+		}
+	}
+
+	return l.checkFields(sField, value, conditions)
+}
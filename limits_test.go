@@ -0,0 +1,174 @@
+package qparams
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseFilterLimits(t *testing.T) {
+	type testStruct struct {
+		Filter Filter `qparams:"maxlength:40 maxconditions:3 maxvaluelen:5 maxfields:2"`
+		Expr   Filter `qparams:"expr:true maxconditions:2 maxdepth:1"`
+		Map    Map    `qparams:"ops:== maxconditions:2"`
+	}
+
+	a := Condition{Field: "a", Op: Eq, RawOp: "==", Value: "1"}
+	b := Condition{Field: "b", Op: Eq, RawOp: "==", Value: "2"}
+
+	table := []testCase{
+		{
+			URL: "foobar.com?filter=a==1,b==2,a==1&expr=(a==1|b==2)&map=a==1,b==2",
+			ExpectedResult: testStruct{
+				Filter: Filter{Conditions: []Condition{a, b, a}},
+				Expr:   Filter{Conditions: []Condition{a, b}, Root: Or{a, b}},
+				Map:    Map{"a ==": "1", "b ==": "2"},
+			},
+			ExpectedError: nil,
+		},
+
+		{
+			URL: "foobar.com?map=a==1,b==2,",
+			ExpectedResult: testStruct{
+				Map: Map{"a ==": "1", "b ==": "2"},
+			},
+			ExpectedError: nil,
+		},
+
+		{
+			URL:            "foobar.com?filter=a==1,b==2,a==1,b==2",
+			ExpectedResult: testStruct{},
+			ExpectedError: TypeConvErrors{
+				"Field Filter contains more than 3 conditions",
+			},
+		},
+
+		{
+			URL:            "foobar.com?filter=a==1,b==2,c==3&map=a==1,b==2,c==3",
+			ExpectedResult: testStruct{},
+			ExpectedError: TypeConvErrors{
+				"Field Filter filters more than 2 distinct fields",
+				"Field Map contains more than 2 conditions",
+			},
+		},
+
+		{
+			URL:            "foobar.com?filter=a==123456,b==2",
+			ExpectedResult: testStruct{Filter: Filter{Conditions: []Condition{b}}},
+			ExpectedError: TypeConvErrors{
+				"Filter field a value is longer than 5 characters",
+			},
+		},
+
+		{
+			URL:            "foobar.com?filter=a==12345,a==12345,a==12345,a==12345,a==12345",
+			ExpectedResult: testStruct{},
+			ExpectedError: TypeConvErrors{
+				"Field Filter is longer than 40 characters",
+			},
+		},
+
+		{
+			URL:            "foobar.com?expr=a==1,b==2,c==3",
+			ExpectedResult: testStruct{},
+			ExpectedError: TypeConvErrors{
+				"Field Expr contains more than 2 conditions",
+			},
+		},
+
+		{
+			URL:            "foobar.com?expr=((a==1))",
+			ExpectedResult: testStruct{},
+			ExpectedError: TypeConvErrors{
+				"Field Expr nests groups deeper than 1",
+			},
+		},
+	}
+
+	t.Log("")
+	t.Log("Testing filter limits")
+
+	for _, c := range table {
+		opts := testStruct{}
+		r := newRequest(c.URL)
+		err := Parse(&opts, r)
+
+		compare(t, c, opts, err)
+	}
+}
+
+func TestParseMapLimits(t *testing.T) {
+	type testStruct struct {
+		Map   Map      `qparams:"ops:== maxvaluelen:3 maxfields:1"`
+		Multi MultiMap `qparams:"ops:== maxvaluelen:3 maxfields:1"`
+	}
+
+	table := []testCase{
+		{
+			URL: "foobar.com?map=a==123&multi=a==1,a==2",
+			ExpectedResult: testStruct{
+				Map:   Map{"a ==": "123"},
+				Multi: MultiMap{"a ==": {"1", "2"}},
+			},
+			ExpectedError: nil,
+		},
+
+		{
+			URL:            "foobar.com?map=a==12345,b==1&multi=a==1234",
+			ExpectedResult: testStruct{},
+			ExpectedError: TypeConvErrors{
+				"Filter field a value is longer than 3 characters",
+				"Filter field a value is longer than 3 characters",
+			},
+		},
+
+		{
+			URL:            "foobar.com?map=a==1,b==1&multi=a==1,b==2",
+			ExpectedResult: testStruct{},
+			ExpectedError: TypeConvErrors{
+				"Field Map filters more than 1 distinct fields",
+				"Field Multi filters more than 1 distinct fields",
+			},
+		},
+	}
+
+	t.Log("")
+	t.Log("Testing map limits")
+
+	for _, c := range table {
+		opts := testStruct{}
+		err := Parse(&opts, newRequest(c.URL))
+
+		compare(t, c, opts, err)
+	}
+}
+
+func TestDecoderLimits(t *testing.T) {
+	type testStruct struct {
+		Filter Filter `qparams:"expr:true"`
+		Other  Filter `qparams:"maxconditions:3"`
+	}
+
+	d := &Decoder{Limits: Limits{MaxConditions: 1, MaxDepth: 1}}
+
+	opts := testStruct{}
+	err := d.Parse(&opts, newRequest("foobar.com?filter=a==1,b==2&other=a==1,b==2"))
+
+	var le *LimitError
+	if !errors.As(err, &le) || le.Limit != "maxconditions" || le.Max != 1 || le.Param != "filter" {
+		t.Fatalf("Incorrect limit error GOT: %#v", err)
+	}
+
+	var fe *FieldError
+	if !errors.As(err, &fe) || fe.Field != "Filter" {
+		t.Fatalf("Limit error should unwrap to field error GOT: %#v", err)
+	}
+
+	if len(opts.Other.Conditions) != 2 {
+		t.Fatalf("Field tag should override decoder limit GOT: %v", opts.Other.Conditions)
+	}
+
+	err = d.Parse(&opts, newRequest("foobar.com?filter=((a==1))"))
+	if !errors.As(err, &le) || le.Limit != "maxdepth" || le.Max != 1 || le.Code != CodeMaxDepth || le.Args["max"] != "1" {
+		t.Fatalf("Incorrect limit error GOT: %#v", err)
+	}
+}
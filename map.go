@@ -1,10 +1,209 @@
 package qparams
 
 import (
+	"errors"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 )
 
+// ErrNoValue is returned by Map accessors if there is no value
+// for field and operator pair
+var ErrNoValue = errors.New("No value")
+
+// MapEntry is a single field, operator and value of Map
+type MapEntry struct {
+	Field string
+	Op    string
+	Value string
+}
+
+// Map returns map[string]string of qparams Map
+func (m Map) Map() map[string]string {
+	return map[string]string(m)
+}
+
+// Has reports whether m has a value for field and operator pair
+func (m Map) Has(field, op string) bool {
+	_, ok := m[mapKey(field, op)]
+	return ok
+}
+
+// value returns value of field and operator pair or ErrNoValue
+func (m Map) value(field, op string) (string, error) {
+	v, ok := m[mapKey(field, op)]
+	if !ok {
+		return "", fmt.Errorf("%w for %s", ErrNoValue, mapKey(field, op))
+	}
+
+	return v, nil
+}
+
+// Strings returns value of field and operator pair split on sep
+// eg. m.Strings("tag", "==", "|") for tag==a|b, nil if there is no value
+func (m Map) Strings(field, op, sep string) []string {
+	v, ok := m[mapKey(field, op)]
+	if !ok {
+		return nil
+	}
+
+	return strings.Split(v, sep)
+}
+
+// Int will attempt to convert value of field and operator pair to int
+// eg. m.Int("amount", ">="). Will return ErrNoValue if there is no
+// value or conversion error on failure
+func (m Map) Int(field, op string) (int, error) {
+	v, err := m.value(field, op)
+	if err != nil {
+		return 0, err
+	}
+
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("Could not convert %s value %s to int", mapKey(field, op), v)
+	}
+
+	return i, nil
+}
+
+// Float will attempt to convert value of field and operator pair to
+// float64. Will return ErrNoValue if there is no value or conversion
+// error on failure
+func (m Map) Float(field, op string) (float64, error) {
+	v, err := m.value(field, op)
+	if err != nil {
+		return 0, err
+	}
+
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, fmt.Errorf("Could not convert %s value %s to float", mapKey(field, op), v)
+	}
+
+	return f, nil
+}
+
+// Bool will attempt to convert value of field and operator pair to
+// bool. Will return ErrNoValue if there is no value or conversion
+// error on failure
+func (m Map) Bool(field, op string) (bool, error) {
+	v, err := m.value(field, op)
+	if err != nil {
+		return false, err
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("Could not convert %s value %s to bool", mapKey(field, op), v)
+	}
+
+	return b, nil
+}
+
+// Time will attempt to parse value of field and operator pair with
+// layout eg. m.Time("created", ">=", "2006-01-02"). Will return
+// ErrNoValue if there is no value or conversion error on failure
+func (m Map) Time(field, op, layout string) (time.Time, error) {
+	v, err := m.value(field, op)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	t, err := time.Parse(layout, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Could not convert %s value %s to time (%s)", mapKey(field, op), v, layout)
+	}
+
+	return t, nil
+}
+
+// Range returns bounds of field and operator pair range value eg.
+// m.Range("amount", "=") for amount=10..100, an empty bound means
+// the range is open on that side. Numeric bounds are checked to be
+// ordered. Will return ErrNoValue if there is no value or error if
+// value is not a valid range
+func (m Map) Range(field, op string) (Bounds, error) {
+	v, err := m.value(field, op)
+	if err != nil {
+		return Bounds{}, err
+	}
+
+	b, ok := splitRange(v)
+	if !ok {
+		return Bounds{}, fmt.Errorf("Could not convert %s value %s to range", mapKey(field, op), v)
+	}
+
+	lower, upper, _ := b.convert(valueType{kind: "string"})
+	if !boundsOrdered(lower, upper) {
+		return Bounds{}, fmt.Errorf("%s range lower bound is greater than upper bound (%s)", mapKey(field, op), v)
+	}
+
+	return b, nil
+}
+
+// TimeRange will attempt to parse range value of field and operator
+// pair with layout eg. m.TimeRange("created", "=", "2006-01-02") for
+// created=2024-01-01..2024-02-01, missing bounds are nil. Will return
+// ErrNoValue if there is no value, or error if value is not a valid
+// range or its lower bound is after upper bound
+func (m Map) TimeRange(field, op, layout string) (Range[time.Time], error) {
+	b, err := m.Range(field, op)
+	if err != nil {
+		return Range[time.Time]{}, err
+	}
+
+	lower, upper, err := b.convert(valueType{kind: "time", layout: layout})
+	if err != nil {
+		return Range[time.Time]{}, fmt.Errorf("Could not convert %s value %s to time range (%s)", mapKey(field, op), m[mapKey(field, op)], layout)
+	}
+
+	if !boundsOrdered(lower, upper) {
+		return Range[time.Time]{}, fmt.Errorf("%s range lower bound is greater than upper bound (%s)", mapKey(field, op), m[mapKey(field, op)])
+	}
+
+	return Range[time.Time]{Lower: toBound[time.Time](lower, timeType), Upper: toBound[time.Time](upper, timeType)}, nil
+}
+
+// Entries returns all entries of m sorted by field and operator
+func (m Map) Entries() []MapEntry {
+	entries := make([]MapEntry, 0, len(m))
+
+	for key, value := range m {
+		field, op := splitMapKey(key)
+		entries = append(entries, MapEntry{Field: field, Op: op, Value: value})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Field != entries[j].Field {
+			return entries[i].Field < entries[j].Field
+		}
+		return entries[i].Op < entries[j].Op
+	})
+
+	return entries
+}
+
+// Each calls fn with field, operator and value of every entry
+// sorted by field and operator
+func (m Map) Each(fn func(field, op, value string)) {
+	for _, e := range m.Entries() {
+		fn(e.Field, e.Op, e.Value)
+	}
+}
+
+// splitMapKey splits Map key into field and operator
+func splitMapKey(key string) (field, op string) {
+	i := strings.LastIndex(key, " ")
+	if i == -1 {
+		return key, ""
+	}
+
+	return key[:i], key[i+1:]
+}
+
 func isOperator(c string, operators []string) (bool, int) {
 
 	for _, o := range operators {
@@ -59,6 +258,16 @@ func getValue(str, separator string) (string, int) {
 func walk(filterRaw string, separator string, operators []string) map[string]string {
 	filters := make(Map)
 
+	walkFunc(filterRaw, separator, operators, func(key, value string) {
+		filters[key] = value
+	})
+
+	return filters
+}
+
+// walkFunc calls fn with key (field and operator) and value
+// of every condition in order
+func walkFunc(filterRaw string, separator string, operators []string, fn func(key, value string)) {
 	strSlice := strings.Split(filterRaw, separator)
 	for _, filter := range strSlice {
 		if filter == "" {
@@ -90,11 +299,9 @@ func walk(filterRaw string, separator string, operators []string) map[string]str
 			}
 
 			value, _ := getValue(filter[i+off:], separator)
-			key := fmt.Sprintf("%s %s", strings.ToLower(chunk), op)
+			key := mapKey(chunk, op)
 
-			filters[key] = value
+			fn(key, value)
 		}
 	}
-
-	return filters
 }
@@ -0,0 +1,133 @@
+package qparams
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMapAccessors(t *testing.T) {
+	type testStruct struct {
+		Filter Map `qparams:"ops:==,>=,<"`
+	}
+
+	opts := testStruct{}
+	err := Parse(&opts, newRequest("foobar.com?filter=amount>=100,Score<2.5,paid==true,created>=2024-01-02,tag==a|b,name==x"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	m := opts.Filter
+
+	if i, err := m.Int("amount", ">="); err != nil || i != 100 {
+		t.Fatalf("Incorrect int GOT: %v %v", i, err)
+	}
+
+	if f, err := m.Float("score", "<"); err != nil || f != 2.5 {
+		t.Fatalf("Incorrect float GOT: %v %v", f, err)
+	}
+
+	if b, err := m.Bool("paid", "=="); err != nil || !b {
+		t.Fatalf("Incorrect bool GOT: %v %v", b, err)
+	}
+
+	want := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	if tm, err := m.Time("created", ">=", "2006-01-02"); err != nil || !tm.Equal(want) {
+		t.Fatalf("Incorrect time GOT: %v %v", tm, err)
+	}
+
+	if s := m.Strings("tag", "==", "|"); !reflect.DeepEqual(s, []string{"a", "b"}) {
+		t.Fatalf("Incorrect strings GOT: %v", s)
+	}
+
+	if !m.Has("name", "==") || m.Has("name", ">=") {
+		t.Fatalf("Incorrect has")
+	}
+
+	if _, err := m.Int("missing", "=="); !errors.Is(err, ErrNoValue) {
+		t.Fatalf("Incorrect error WANT: %v GOT: %v", ErrNoValue, err)
+	}
+
+	if _, err := m.Int("name", "=="); err == nil || err.Error() != "Could not convert name == value x to int" {
+		t.Fatalf("Incorrect conversion error GOT: %v", err)
+	}
+
+	if _, err := m.Time("amount", ">=", time.RFC3339); err == nil {
+		t.Fatalf("Expected time conversion error")
+	}
+
+	if !reflect.DeepEqual(m.Map(), map[string]string(m)) {
+		t.Fatalf("Incorrect map GOT: %v", m.Map())
+	}
+}
+
+func TestMapEntries(t *testing.T) {
+	m := Map{"b ==": "2", "a >=": "1", "a <": "9"}
+
+	want := []MapEntry{
+		{Field: "a", Op: "<", Value: "9"},
+		{Field: "a", Op: ">=", Value: "1"},
+		{Field: "b", Op: "==", Value: "2"},
+	}
+
+	if e := m.Entries(); !reflect.DeepEqual(e, want) {
+		t.Fatalf("Incorrect entries WANT: %v GOT: %v", want, e)
+	}
+
+	var got []MapEntry
+	m.Each(func(field, op, value string) {
+		got = append(got, MapEntry{field, op, value})
+	})
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Incorrect each WANT: %v GOT: %v", want, got)
+	}
+}
+
+func TestMapRange(t *testing.T) {
+	type testStruct struct {
+		Filter Map `qparams:"ops:="`
+	}
+
+	opts := testStruct{}
+	err := Parse(&opts, newRequest("foobar.com?filter=amount=10..100,score=5..,price=100..10,name=x,created=2024-01-01..2024-02-01,deleted=2024-02-01..2024-01-01"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	m := opts.Filter
+
+	if b, err := m.Range("amount", "="); err != nil || b != (Bounds{Lower: "10", Upper: "100"}) {
+		t.Fatalf("Incorrect range GOT: %v %v", b, err)
+	}
+
+	if b, err := m.Range("score", "="); err != nil || b != (Bounds{Lower: "5"}) {
+		t.Fatalf("Incorrect half open range GOT: %v %v", b, err)
+	}
+
+	if _, err := m.Range("price", "="); err == nil || err.Error() != "price = range lower bound is greater than upper bound (100..10)" {
+		t.Fatalf("Incorrect order error GOT: %v", err)
+	}
+
+	if _, err := m.Range("name", "="); err == nil || err.Error() != "Could not convert name = value x to range" {
+		t.Fatalf("Incorrect range error GOT: %v", err)
+	}
+
+	if _, err := m.Range("missing", "="); !errors.Is(err, ErrNoValue) {
+		t.Fatalf("Incorrect error WANT: %v GOT: %v", ErrNoValue, err)
+	}
+
+	r, err := m.TimeRange("created", "=", "2006-01-02")
+	if err != nil || !r.Lower.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) || !r.Upper.Equal(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Incorrect time range GOT: %v %v", r, err)
+	}
+
+	if _, err := m.TimeRange("deleted", "=", "2006-01-02"); err == nil {
+		t.Fatalf("Expected time range order error")
+	}
+
+	if _, err := m.TimeRange("amount", "=", "2006-01-02"); err == nil {
+		t.Fatalf("Expected time range conversion error")
+	}
+}
@@ -0,0 +1,248 @@
+package qparams
+
+import (
+	"context"
+	"errors"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrorCode identifies the kind of failure described by FieldError,
+// it selects the message template of MessageCatalog
+type ErrorCode string
+
+// Error codes of FieldError
+const (
+	CodeInvalidInt           ErrorCode = "invalid_int"
+	CodeInvalidFloat         ErrorCode = "invalid_float"
+	CodeInvalidDecimal       ErrorCode = "invalid_decimal"
+	CodeInvalidElement       ErrorCode = "invalid_element"
+	CodeInvalidRange         ErrorCode = "invalid_range"
+	CodeInvalidTypedRange    ErrorCode = "invalid_typed_range"
+	CodeRangeOrder           ErrorCode = "range_order"
+	CodeInvalidSort          ErrorCode = "invalid_sort"
+	CodeInvalidCondition     ErrorCode = "invalid_condition"
+	CodeExprUnexpectedToken  ErrorCode = "expr_unexpected_token"
+	CodeExprUnclosedGroup    ErrorCode = "expr_unclosed_group"
+	CodeExprMissingCondition ErrorCode = "expr_missing_condition"
+	CodeFilterEmptySet       ErrorCode = "filter_empty_set"
+	CodeFilterInvalidRange   ErrorCode = "filter_invalid_range"
+	CodeFilterNullValue      ErrorCode = "filter_null_value"
+	CodeFilterEmptyPattern   ErrorCode = "filter_empty_pattern"
+	CodeFilterNotAllowed     ErrorCode = "filter_not_allowed"
+	CodeFilterInvalidValue   ErrorCode = "filter_invalid_value"
+	CodeFilterRangeOrder     ErrorCode = "filter_range_order"
+	CodeMaxLength            ErrorCode = "max_length"
+	CodeMaxConditions        ErrorCode = "max_conditions"
+	CodeMaxValueLength       ErrorCode = "max_value_length"
+	CodeMaxFields            ErrorCode = "max_fields"
+	CodeMaxDepth             ErrorCode = "max_depth"
+	CodeMaxNodes             ErrorCode = "max_nodes"
+)
+
+// Messages maps error codes to message templates of a single language.
+// Templates refer to FieldError values with {field}, {param} and {value}
+// placeholders and to Args with {name} eg. {type} or {max}. Templates of
+// a single query param are overridden with param.code keys eg.
+//
+//	qparams.Messages{"page.invalid_int": "Seite muss eine Zahl sein"}
+type Messages map[string]string
+
+// English are the default message templates, FieldError Reason
+// is rendered from them
+var English = Messages{
+	string(CodeInvalidInt):           "Field {field} does not contain a valid integer ({value})",
+	string(CodeInvalidFloat):         "Field {field} does not contain a valid float ({value})",
+	string(CodeInvalidDecimal):       "Field {field} does not contain a valid decimal ({value})",
+	string(CodeInvalidElement):       "Field {field} member {index} does not contain a valid {type} ({value})",
+	string(CodeInvalidRange):         "Field {field} does not contain a valid range ({value})",
+	string(CodeInvalidTypedRange):    "Field {field} does not contain a valid {type} range ({value})",
+	string(CodeRangeOrder):           "Field {field} range lower bound is greater than upper bound ({value})",
+	string(CodeInvalidSort):          "Field {field} contains invalid sort field ({value})",
+	string(CodeInvalidCondition):     "Field {field} contains invalid filter condition ({value})",
+	string(CodeExprUnexpectedToken):  "Field {field} contains invalid filter expression at position {position}, unexpected {token} ({value})",
+	string(CodeExprUnclosedGroup):    "Field {field} contains invalid filter expression at position {position}, expected {token} ({value})",
+	string(CodeExprMissingCondition): "Field {field} contains invalid filter expression at position {position}, expected condition ({value})",
+	string(CodeFilterEmptySet):       "Filter field {field} contains an empty set ({value})",
+	string(CodeFilterInvalidRange):   "Filter field {field} contains an invalid range ({value})",
+	string(CodeFilterNullValue):      "Filter field {field} null check does not take a value ({value})",
+	string(CodeFilterEmptyPattern):   "Filter field {field} contains an empty pattern",
+	string(CodeFilterNotAllowed):     "Filter field {field} is not filterable",
+	string(CodeFilterInvalidValue):   "Filter field {field} does not contain a valid {type} ({value})",
+	string(CodeFilterRangeOrder):     "Filter field {field} range lower bound is greater than upper bound ({value})",
+	string(CodeMaxLength):            "Field {field} is longer than {max} characters",
+	string(CodeMaxConditions):        "Field {field} contains more than {max} conditions",
+	string(CodeMaxValueLength):       "Filter field {filter} value is longer than {max} characters",
+	string(CodeMaxFields):            "Field {field} filters more than {max} distinct fields",
+	string(CodeMaxDepth):             "Field {field} nests groups deeper than {max}",
+	string(CodeMaxNodes):             "Field {field} contains more than {max} expression nodes",
+}
+
+// MessageCatalog provides message templates of error codes by language
+type MessageCatalog interface {
+	Message(lang, param string, code ErrorCode) (template string, ok bool)
+}
+
+// Catalog is a MessageCatalog of Messages by language tag eg. "de".
+// Regional tags fall back to their base language, de-AT to de
+type Catalog map[string]Messages
+
+// Message implements MessageCatalog
+func (c Catalog) Message(lang, param string, code ErrorCode) (string, bool) {
+	lang = strings.ToLower(lang)
+
+	for {
+		if m, ok := c[lang]; ok {
+			if t, ok := m.message(param, code); ok {
+				return t, true
+			}
+		}
+
+		i := strings.LastIndex(lang, "-")
+		if i == -1 {
+			return "", false
+		}
+
+		lang = lang[:i]
+	}
+}
+
+// message returns template of code, param override first
+func (m Messages) message(param string, code ErrorCode) (string, bool) {
+	if param != "" {
+		if t, ok := m[param+"."+string(code)]; ok {
+			return t, true
+		}
+	}
+
+	t, ok := m[string(code)]
+	return t, ok
+}
+
+// newFieldError returns FieldError of code with Reason
+// rendered from English template
+func newFieldError(code ErrorCode, field, value string, args map[string]string) *FieldError {
+	e := &FieldError{
+		Field: field,
+		Value: value,
+		Code:  code,
+		Args:  args,
+	}
+
+	e.Reason = e.format(English[string(code)])
+
+	return e
+}
+
+// format replaces template placeholders with error values
+func (e *FieldError) format(template string) string {
+	pairs := []string{"{field}", e.Field, "{param}", e.Param, "{value}", e.Value}
+
+	for k, v := range e.Args {
+		pairs = append(pairs, "{"+k+"}", v)
+	}
+
+	return strings.NewReplacer(pairs...).Replace(template)
+}
+
+// Localize returns message of e in the first of langs having a
+// template for its code in c, Reason if there is none. English
+// languages without template in c use the English Reason
+func (e *FieldError) Localize(c MessageCatalog, langs ...string) string {
+	if e.Code == "" || c == nil {
+		return e.Reason
+	}
+
+	for _, lang := range langs {
+		if t, ok := c.Message(lang, e.Param, e.Code); ok {
+			return e.format(t)
+		}
+
+		if isEnglish(lang) {
+			return e.Reason
+		}
+	}
+
+	return e.Reason
+}
+
+// isEnglish reports whether language tag lang is en or en-*
+func isEnglish(lang string) bool {
+	lang = strings.ToLower(lang)
+
+	return lang == "en" || strings.HasPrefix(lang, "en-")
+}
+
+// localizeErrors sets Reason of every FieldError of err
+// to its message in langs
+func localizeErrors(err error, c MessageCatalog, langs []string) {
+	walkErrors(err, func(e error) {
+		var fe *FieldError
+		if errors.As(e, &fe) {
+			fe.Reason = fe.Localize(c, langs...)
+		}
+	})
+}
+
+type languageKey struct{}
+
+// WithLanguage returns copy of ctx carrying language tag lang,
+// it takes precedence over Accept-Language header
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, languageKey{}, lang)
+}
+
+// LanguageFromContext returns language tag set with WithLanguage
+func LanguageFromContext(ctx context.Context) (string, bool) {
+	lang, ok := ctx.Value(languageKey{}).(string)
+	return lang, ok && lang != ""
+}
+
+// Languages returns preferred languages of r, context language
+// first followed by Accept-Language tags by descending quality
+func Languages(r *http.Request) []string {
+	var langs []string
+
+	if lang, ok := LanguageFromContext(r.Context()); ok {
+		langs = append(langs, lang)
+	}
+
+	type tag struct {
+		lang string
+		q    float64
+	}
+
+	var tags []tag
+
+	for _, part := range strings.Split(r.Header.Get("Accept-Language"), ",") {
+		// language tags parse as media types without subtype
+		lang, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil || lang == "*" {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+
+		if q > 0 {
+			tags = append(tags, tag{lang, q})
+		}
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].q > tags[j].q
+	})
+
+	for _, t := range tags {
+		langs = append(langs, t.lang)
+	}
+
+	return langs
+}
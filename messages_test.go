@@ -0,0 +1,238 @@
+package qparams
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+var testCatalog = Catalog{
+	"de": {
+		string(CodeInvalidInt):              "Feld {field} enthält keine gültige Ganzzahl ({value})",
+		string(CodeFilterInvalidValue):      "Filterfeld {field} enthält keinen gültigen Wert vom Typ {type} ({value})",
+		"page." + string(CodeInvalidInt):    "Seite muss eine Zahl sein",
+		"limit." + string(CodeMaxLength):    "Zu lang",
+		string(CodeInvalidElement):          "Element {index} von {param} ist ungültig",
+		"ratio." + string(CodeInvalidFloat): "Verhältnis ungültig",
+	},
+	"fr": {
+		string(CodeInvalidInt): "Le champ {field} ne contient pas un entier valide ({value})",
+	},
+}
+
+func TestLocalizeErrors(t *testing.T) {
+	type testStruct struct {
+		Limit  int
+		Page   int
+		Ratio  float64
+		IDs    SliceOf[int] `qparams:"name:ids"`
+		Filter FilterOf[struct{ Age int }]
+		Sort   Sort
+	}
+
+	table := []struct {
+		lang   string
+		accept string
+		want   []string
+	}{
+		{
+			"", "de-CH, en;q=0.5",
+			[]string{
+				"Feld Limit enthält keine gültige Ganzzahl (x)",
+				"Seite muss eine Zahl sein",
+				"Verhältnis ungültig",
+				"Element 1 von ids ist ungültig",
+				"Filterfeld age enthält keinen gültigen Wert vom Typ int (y)",
+				"Field Sort contains invalid sort field (-)",
+			},
+		},
+		{
+			"", "it, fr;q=0.8, de;q=0.5",
+			[]string{
+				"Le champ Limit ne contient pas un entier valide (x)",
+				"Le champ Page ne contient pas un entier valide (z)",
+				"Verhältnis ungültig",
+				"Element 1 von ids ist ungültig",
+				"Filterfeld age enthält keinen gültigen Wert vom Typ int (y)",
+				"Field Sort contains invalid sort field (-)",
+			},
+		},
+		{
+			"fr", "de",
+			[]string{
+				"Le champ Limit ne contient pas un entier valide (x)",
+				"Le champ Page ne contient pas un entier valide (z)",
+				"Verhältnis ungültig",
+				"Element 1 von ids ist ungültig",
+				"Filterfeld age enthält keinen gültigen Wert vom Typ int (y)",
+				"Field Sort contains invalid sort field (-)",
+			},
+		},
+		{
+			"", "en, de;q=0.5",
+			[]string{
+				"Field Limit does not contain a valid integer (x)",
+				"Field Page does not contain a valid integer (z)",
+				"Field Ratio does not contain a valid float (r)",
+				"Field IDs member 1 does not contain a valid int (b)",
+				"Filter field age does not contain a valid int (y)",
+				"Field Sort contains invalid sort field (-)",
+			},
+		},
+		{
+			"", "",
+			[]string{
+				"Field Limit does not contain a valid integer (x)",
+				"Field Page does not contain a valid integer (z)",
+				"Field Ratio does not contain a valid float (r)",
+				"Field IDs member 1 does not contain a valid int (b)",
+				"Filter field age does not contain a valid int (y)",
+				"Field Sort contains invalid sort field (-)",
+			},
+		},
+	}
+
+	d := &Decoder{Catalog: testCatalog}
+
+	for i, c := range table {
+		r := newRequest("foobar.com?limit=x&page=z&ratio=r&ids=1,b&filter=age==y&sort=-")
+		r.Header.Set("Accept-Language", c.accept)
+
+		if c.lang != "" {
+			r = r.WithContext(WithLanguage(r.Context(), c.lang))
+		}
+
+		var got testStruct
+
+		err := d.Parse(&got, r)
+
+		var reasons []string
+		walkErrors(err, func(e error) {
+			reasons = append(reasons, e.Error())
+		})
+
+		if !reflect.DeepEqual(reasons, c.want) {
+			t.Fatalf("Incorrect messages for case %d GOT: %q", i, reasons)
+		}
+	}
+}
+
+func TestFieldErrorCodes(t *testing.T) {
+	type testStruct struct {
+		Limit  int
+		Filter Filter `qparams:"maxconditions:1"`
+		Expr   Filter `qparams:"expr:true maxconditions:1"`
+	}
+
+	var got testStruct
+
+	err := ParseQuery(&got, "limit=x&filter=a==1,b==2&expr=a==1|b==2")
+
+	var codes []ErrorCode
+	walkErrors(err, func(e error) {
+		var fe *FieldError
+		if errors.As(e, &fe) {
+			codes = append(codes, fe.Code)
+		}
+	})
+
+	if !reflect.DeepEqual(codes, []ErrorCode{CodeInvalidInt, CodeMaxConditions, CodeMaxConditions}) {
+		t.Fatalf("Incorrect codes GOT: %v", codes)
+	}
+
+	var lErr *LimitError
+	if !errors.As(err, &lErr) || lErr.Args["max"] != "1" || lErr.Reason != "Field Filter contains more than 1 conditions" {
+		t.Fatalf("Incorrect limit error GOT: %#v", lErr)
+	}
+
+	custom := &FieldError{Field: "From", Reason: "From must be before To"}
+	if got := custom.Localize(testCatalog, "de"); got != "From must be before To" {
+		t.Fatalf("Errors without code should keep reason GOT: %s", got)
+	}
+}
+
+func TestLanguages(t *testing.T) {
+	table := []struct {
+		accept string
+		lang   string
+		want   []string
+	}{
+		{"", "", nil},
+		{"de-CH, fr;q=0.9, en;q=0.8, *;q=0.5", "", []string{"de-ch", "fr", "en"}},
+		{"en;q=0.2, de", "", []string{"de", "en"}},
+		{"en;q=0, de;q=x, fr", "", []string{"fr"}},
+		{"de", "sr-Latn", []string{"sr-Latn", "de"}},
+	}
+
+	for i, c := range table {
+		r, _ := http.NewRequest("GET", "foobar.com", nil)
+		r.Header.Set("Accept-Language", c.accept)
+
+		if c.lang != "" {
+			r = r.WithContext(WithLanguage(r.Context(), c.lang))
+		}
+
+		if got := Languages(r); !reflect.DeepEqual(got, c.want) {
+			t.Fatalf("Incorrect languages for case %d GOT: %q", i, got)
+		}
+	}
+}
+
+func TestCatalogFallback(t *testing.T) {
+	table := []struct {
+		lang  string
+		param string
+		code  ErrorCode
+		want  string
+		ok    bool
+	}{
+		{"de", "page", CodeInvalidInt, "Seite muss eine Zahl sein", true},
+		{"de-AT", "limit", CodeInvalidInt, "Feld {field} enthält keine gültige Ganzzahl ({value})", true},
+		{"DE-at-x", "", CodeInvalidInt, "Feld {field} enthält keine gültige Ganzzahl ({value})", true},
+		{"fr-CA", "page", CodeInvalidInt, "Le champ {field} ne contient pas un entier valide ({value})", true},
+		{"fr", "", CodeInvalidFloat, "", false},
+		{"it", "", CodeInvalidInt, "", false},
+	}
+
+	for i, c := range table {
+		got, ok := testCatalog.Message(c.lang, c.param, c.code)
+		if got != c.want || ok != c.ok {
+			t.Fatalf("Incorrect message for case %d GOT: %q %v", i, got, ok)
+		}
+	}
+}
+
+func TestLocalizeExprErrors(t *testing.T) {
+	type testStruct struct {
+		Filter Filter `qparams:"expr:true"`
+	}
+
+	d := &Decoder{Catalog: Catalog{"de": {
+		string(CodeExprUnclosedGroup):    "Feld {field}: {token} fehlt an Position {position}",
+		string(CodeExprUnexpectedToken):  "Feld {field}: {token} an Position {position} unerwartet",
+		string(CodeExprMissingCondition): "Feld {field}: Bedingung fehlt an Position {position}",
+	}}}
+
+	table := []struct {
+		query string
+		code  ErrorCode
+		want  string
+	}{
+		{"(a==1", CodeExprUnclosedGroup, "Feld Filter: ) fehlt an Position 5"},
+		{"a==1)", CodeExprUnexpectedToken, "Feld Filter: ) an Position 4 unerwartet"},
+		{"a==1,", CodeExprMissingCondition, "Feld Filter: Bedingung fehlt an Position 5"},
+	}
+
+	for _, c := range table {
+		r := newRequest("foobar.com?filter=" + c.query)
+		r.Header.Set("Accept-Language", "de")
+
+		var got testStruct
+
+		var fe *FieldError
+		if err := d.Parse(&got, r); !errors.As(err, &fe) || fe.Code != c.code || fe.Reason != c.want {
+			t.Fatalf("Incorrect error for %s GOT: %#v", c.query, fe)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package qparams
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// ErrorRenderer writes the response of a request whose
+// params could not be parsed
+type ErrorRenderer interface {
+	RenderError(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// ErrorRendererFunc adapts func to ErrorRenderer
+type ErrorRendererFunc func(w http.ResponseWriter, r *http.Request, err error)
+
+// RenderError implements ErrorRenderer
+func (f ErrorRendererFunc) RenderError(w http.ResponseWriter, r *http.Request, err error) {
+	f(w, r, err)
+}
+
+// TextRenderer writes err as plain text, one message per line, with
+// 413 status for form bodies over the size limit and 400 otherwise
+var TextRenderer ErrorRenderer = ErrorRendererFunc(func(w http.ResponseWriter, r *http.Request, err error) {
+	http.Error(w, strings.TrimSuffix(err.Error(), "\n"), errorStatus(err))
+})
+
+// Options configure Middleware and Handler
+type Options struct {
+	// Decoder parses request params, default decoder is used if nil
+	Decoder *Decoder
+
+	// Renderer writes the response on failure, TextRenderer if nil
+	Renderer ErrorRenderer
+}
+
+func (o Options) decoder() *Decoder {
+	if o.Decoder == nil {
+		return defaultDecoder
+	}
+
+	return o.Decoder
+}
+
+func (o Options) renderer() ErrorRenderer {
+	if o.Renderer == nil {
+		return TextRenderer
+	}
+
+	return o.Renderer
+}
+
+// contextKey is the context key of params of type T
+type contextKey[T any] struct{}
+
+// Middleware returns middleware parsing and validating request params
+// to T once and storing them in the request context, read them with
+// FromContext. Handler is not called if parsing or validation fails,
+// the error is written with opts Renderer instead. Middleware panics
+// if T is not a struct
+//
+//	mux.Handle("GET /orders", qparams.Middleware[OrderParams](qparams.Options{})(ordersHandler))
+func Middleware[T any](opts Options) func(http.Handler) http.Handler {
+	if err := Prepare[T](); err != nil {
+		panic(err)
+	}
+
+	d, renderer := opts.decoder(), opts.renderer()
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			params, err := parseRequest[T](d, r)
+			if err != nil {
+				renderer.RenderError(w, r, err)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), params)))
+		})
+	}
+}
+
+// NewContext returns copy of ctx carrying params
+func NewContext[T any](ctx context.Context, params T) context.Context {
+	return context.WithValue(ctx, contextKey[T]{}, params)
+}
+
+// FromContext returns params of type T stored in ctx by Middleware,
+// ok is false if there are none
+//
+//	params, ok := qparams.FromContext[OrderParams](r.Context())
+func FromContext[T any](ctx context.Context) (params T, ok bool) {
+	params, ok = ctx.Value(contextKey[T]{}).(T)
+	return params, ok
+}
+
+// errorStatus returns response status of parse error
+func errorStatus(err error) int {
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		return http.StatusRequestEntityTooLarge
+	}
+
+	return http.StatusBadRequest
+}
@@ -0,0 +1,91 @@
+package qparams
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type middlewareParams struct {
+	Limit  int `qparams:"default:10"`
+	Filter Filter
+}
+
+func TestMiddleware(t *testing.T) {
+	var got middlewareParams
+	var called bool
+
+	h := Middleware[middlewareParams](Options{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, called = FromContext[middlewareParams](r.Context())
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newRequest("foobar.com?filter=age>=3"))
+
+	if !called || w.Code != http.StatusOK || got.Limit != 10 || len(got.Filter.Conditions) != 1 {
+		t.Fatalf("Incorrect result GOT: %+v %v %d", got, called, w.Code)
+	}
+
+	called = false
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, newRequest("foobar.com?limit=x"))
+
+	if called || w.Code != http.StatusBadRequest || w.Body.String() != "Field Limit does not contain a valid integer (x)\n" {
+		t.Fatalf("Incorrect error response GOT: %v %d %q", called, w.Code, w.Body.String())
+	}
+}
+
+func TestMiddlewareOptions(t *testing.T) {
+	var rendered error
+
+	opts := Options{
+		Decoder: &Decoder{Source: SourceForm, MaxBodySize: 4},
+		Renderer: ErrorRendererFunc(func(w http.ResponseWriter, r *http.Request, err error) {
+			rendered = err
+			w.WriteHeader(http.StatusTeapot)
+		}),
+	}
+
+	h := Middleware[middlewareParams](opts)(http.NotFoundHandler())
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, newFormRequest("foobar.com", "limit=12345"))
+
+	if rendered == nil || w.Code != http.StatusTeapot {
+		t.Fatalf("Renderer should be called GOT: %v %d", rendered, w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	TextRenderer.RenderError(w, nil, rendered)
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("Incorrect status GOT: %d", w.Code)
+	}
+}
+
+func TestMiddlewareWrongType(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Middleware should panic for non-struct params")
+		}
+	}()
+
+	Middleware[int](Options{})
+}
+
+func TestFromContext(t *testing.T) {
+	if _, ok := FromContext[middlewareParams](context.Background()); ok {
+		t.Fatal("Empty context should have no params")
+	}
+
+	ctx := NewContext(context.Background(), middlewareParams{Limit: 5})
+
+	if p, ok := FromContext[middlewareParams](ctx); !ok || p.Limit != 5 {
+		t.Fatalf("Incorrect params GOT: %+v %v", p, ok)
+	}
+
+	if _, ok := FromContext[*middlewareParams](ctx); ok {
+		t.Fatal("Params of other type should not be found")
+	}
+}
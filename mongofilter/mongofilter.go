@@ -0,0 +1,193 @@
+// Package mongofilter translates parsed qparams filters to MongoDB
+// query documents
+//
+// Documents are plain map[string]any values using query operators
+// eg. $eq, $in, $regex, $and, so the package does not depend on
+// the MongoDB driver
+package mongofilter
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/tonto/qparams"
+)
+
+// Options configure filter translation
+type Options struct {
+	// Mapper resolves filter fields to document paths eg.
+	// qparams.FieldMapOf[Order]("bson"), unmapped fields are
+	// rejected. Every field is rejected if it is nil
+	Mapper qparams.FieldMapper
+
+	// Coerce converts condition values before they are put into
+	// the document eg. to driver decimal or object id types.
+	// It is called for every value, including set members and bounds,
+	// decimal values are passed as qparams.Decimal. Without it
+	// decimals are converted to float64
+	Coerce func(field string, v any) (any, error)
+}
+
+var (
+	// ErrUnsupportedOp is returned for conditions with Custom operator
+	ErrUnsupportedOp = errors.New("mongofilter: unsupported operator")
+
+	// ErrUnknownField is returned for fields not mapped by Mapper
+	ErrUnknownField = errors.New("mongofilter: unknown field")
+)
+
+var comparisons = map[qparams.Op]string{
+	qparams.Eq:  "$eq",
+	qparams.Ne:  "$ne",
+	qparams.Gt:  "$gt",
+	qparams.Gte: "$gte",
+	qparams.Lt:  "$lt",
+	qparams.Lte: "$lte",
+}
+
+// Filter translates filter f to query document, empty filter
+// yields an empty document
+func Filter(f qparams.Filter, opts Options) (map[string]any, error) {
+	return opts.node(f.Expr())
+}
+
+func (o Options) node(n qparams.Node) (map[string]any, error) {
+	var nodes []qparams.Node
+	var op string
+
+	switch n := n.(type) {
+	case qparams.And:
+		nodes, op = n, "$and"
+	case qparams.Or:
+		nodes, op = n, "$or"
+	case qparams.Not:
+		doc, err := o.node(n.Node)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"$nor": []any{doc}}, nil
+	case qparams.Condition:
+		return o.condition(n)
+	}
+
+	if len(nodes) == 0 {
+		return map[string]any{}, nil
+	}
+
+	if len(nodes) == 1 {
+		return o.node(nodes[0])
+	}
+
+	docs := make([]any, 0, len(nodes))
+
+	for _, n := range nodes {
+		doc, err := o.node(n)
+		if err != nil {
+			return nil, err
+		}
+
+		docs = append(docs, doc)
+	}
+
+	return map[string]any{op: docs}, nil
+}
+
+func (o Options) condition(c qparams.Condition) (map[string]any, error) {
+	field, err := o.field(c.Field)
+	if err != nil {
+		return nil, err
+	}
+
+	var expr map[string]any
+
+	switch {
+	case c.Pattern != nil:
+		p := *c.Pattern
+		p.Fold = false
+
+		expr = map[string]any{"$regex": p.Regexp()}
+		if c.Pattern.Fold {
+			expr["$options"] = "i"
+		}
+	case c.Op == qparams.IsNull:
+		if c.Not {
+			return map[string]any{field: map[string]any{"$ne": nil}}, nil
+		}
+		return map[string]any{field: map[string]any{"$eq": nil}}, nil
+	case c.Op == qparams.In:
+		values := []any{}
+		for _, v := range c.TypedValues() {
+			v, err := o.coerce(c.Field, v)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v)
+		}
+
+		op := "$in"
+		if c.Not {
+			op = "$nin"
+		}
+
+		return map[string]any{field: map[string]any{op: values}}, nil
+	case c.Range != nil:
+		expr = map[string]any{}
+
+		lower, upper := c.TypedBounds()
+		for op, v := range map[string]any{"$gte": lower, "$lte": upper} {
+			if v == nil {
+				continue
+			}
+
+			v, err := o.coerce(c.Field, v)
+			if err != nil {
+				return nil, err
+			}
+
+			expr[op] = v
+		}
+	default:
+		op, ok := comparisons[c.Op]
+		if !ok {
+			return nil, fmt.Errorf("%w %s", ErrUnsupportedOp, c.RawOp)
+		}
+
+		v, err := o.coerce(c.Field, c.TypedValue())
+		if err != nil {
+			return nil, err
+		}
+
+		expr = map[string]any{op: v}
+	}
+
+	if c.Not {
+		return map[string]any{field: map[string]any{"$not": expr}}, nil
+	}
+
+	return map[string]any{field: expr}, nil
+}
+
+func (o Options) field(name string) (string, error) {
+	if o.Mapper != nil {
+		if field, ok := o.Mapper.MapField(name); ok {
+			return field, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w %s", ErrUnknownField, name)
+}
+
+// coerce converts v with Coerce, decimals are passed to it unchanged
+// and converted to float64 without it, so that they are compared
+// with numeric fields
+func (o Options) coerce(field string, v any) (any, error) {
+	if o.Coerce != nil {
+		return o.Coerce(field, v)
+	}
+
+	if d, ok := v.(qparams.Decimal); ok {
+		return d.Float64()
+	}
+
+	return v, nil
+}
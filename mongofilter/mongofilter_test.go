@@ -0,0 +1,193 @@
+package mongofilter
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tonto/qparams"
+)
+
+type doc = map[string]any
+
+// passthrough maps every field to itself
+var passthrough = qparams.FieldMapFunc(func(f string) (string, bool) { return f, true })
+
+// cond returns condition of field, operator and value
+func cond(field string, op qparams.Op, value string) qparams.Condition {
+	return qparams.Condition{Field: field, Op: op, Value: value}
+}
+
+// filter returns filter of expression root
+func filter(root qparams.Node) qparams.Filter {
+	return qparams.Filter{Root: root}
+}
+
+func TestFilter(t *testing.T) {
+	amount := cond("amount", qparams.Gt, "5")
+	amount.Typed = qparams.Decimal("5")
+
+	table := []struct {
+		Name   string
+		Filter qparams.Filter
+		Opts   Options
+		Doc    doc
+	}{
+		{
+			Name: "comparisons",
+			Filter: filter(qparams.And{
+				qparams.Condition{Field: "amount", Op: qparams.Gte, Value: "10.5", Typed: qparams.Decimal("10.5")},
+				cond("status", qparams.Eq, "paid"),
+			}),
+			Doc: doc{"$and": []any{
+				doc{"amount": doc{"$gte": 10.5}},
+				doc{"status": doc{"$eq": "paid"}},
+			}},
+		},
+
+		{
+			Name: "groups",
+			Filter: filter(qparams.And{
+				qparams.Or{cond("status", qparams.Eq, "paid"), cond("status", qparams.Ne, "void")},
+				qparams.Condition{Field: "age", Op: qparams.Lt, Value: "18", Typed: 18, Not: true},
+			}),
+			Doc: doc{"$and": []any{
+				doc{"$or": []any{
+					doc{"status": doc{"$eq": "paid"}},
+					doc{"status": doc{"$ne": "void"}},
+				}},
+				doc{"age": doc{"$not": doc{"$lt": 18}}},
+			}},
+		},
+
+		{
+			Name:   "set",
+			Filter: filter(qparams.Condition{Field: "status", Op: qparams.In, Values: []string{"paid", "refunded"}}),
+			Doc:    doc{"status": doc{"$in": []any{"paid", "refunded"}}},
+		},
+
+		{
+			Name: "null",
+			Filter: filter(qparams.And{
+				qparams.Condition{Field: "age", Op: qparams.In, Not: true, Values: []string{"1", "2"}, Typed: []int{1, 2}},
+				qparams.Condition{Field: "deleted", Op: qparams.IsNull},
+				qparams.Condition{Field: "created", Op: qparams.IsNull, Not: true},
+			}),
+			Doc: doc{"$and": []any{
+				doc{"age": doc{"$nin": []any{1, 2}}},
+				doc{"deleted": doc{"$eq": nil}},
+				doc{"created": doc{"$ne": nil}},
+			}},
+		},
+
+		{
+			Name: "ranges",
+			Filter: filter(qparams.And{
+				qparams.Condition{Field: "age", Op: qparams.Between, Range: &qparams.Bounds{Lower: "18", Upper: "65"}, Typed: [2]interface{}{18, 65}},
+				qparams.Condition{Field: "amount", Op: qparams.Between, Range: &qparams.Bounds{Upper: "100"}, Typed: [2]interface{}{nil, qparams.Decimal("100")}},
+			}),
+			Doc: doc{"$and": []any{
+				doc{"age": doc{"$gte": 18, "$lte": 65}},
+				doc{"amount": doc{"$lte": 100.0}},
+			}},
+		},
+
+		{
+			Name: "patterns",
+			Filter: filter(qparams.And{
+				qparams.Condition{Field: "name", Op: qparams.Like, Pattern: &qparams.Pattern{Mode: qparams.MatchLike, Parts: []string{"Jo", "."}}},
+				qparams.Condition{Field: "email", Op: qparams.IContains, Pattern: &qparams.Pattern{Mode: qparams.MatchContains, Parts: []string{"@Example"}, Fold: true}},
+			}),
+			Doc: doc{"$and": []any{
+				doc{"name": doc{"$regex": `^Jo.*\.$`}},
+				doc{"email": doc{"$regex": `^.*@Example.*$`, "$options": "i"}},
+			}},
+		},
+
+		{
+			Name:   "negated group",
+			Filter: filter(qparams.Not{Node: cond("status", qparams.Eq, "paid")}),
+			Doc:    doc{"$nor": []any{doc{"status": doc{"$eq": "paid"}}}},
+		},
+
+		{
+			Name:   "empty",
+			Filter: qparams.Filter{},
+			Doc:    doc{},
+		},
+
+		{
+			Name:   "coerced",
+			Filter: filter(qparams.And{cond("name", qparams.Eq, "john"), amount}),
+			Opts: Options{
+				Mapper: qparams.FieldMapFunc(func(f string) (string, bool) { return "customer." + f, true }),
+				Coerce: func(f string, v any) (any, error) {
+					if d, ok := v.(qparams.Decimal); ok {
+						return "decimal:" + string(d), nil
+					}
+					return strings.ToUpper(v.(string)), nil
+				},
+			},
+			Doc: doc{"$and": []any{
+				doc{"customer.name": doc{"$eq": "JOHN"}},
+				doc{"customer.amount": doc{"$gt": "decimal:5"}},
+			}},
+		},
+	}
+
+	for _, c := range table {
+		if c.Opts.Mapper == nil {
+			c.Opts.Mapper = passthrough
+		}
+
+		got, err := Filter(c.Filter, c.Opts)
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", c.Name, err)
+		}
+
+		if !reflect.DeepEqual(got, c.Doc) {
+			t.Fatalf("Incorrect document for %s\nWANT: %#v\nGOT:  %#v", c.Name, c.Doc, got)
+		}
+	}
+}
+
+func TestFilterErrors(t *testing.T) {
+	f := filter(qparams.Condition{Field: "age", Op: qparams.Custom, RawOp: "~~", Value: "1"})
+
+	if _, err := Filter(f, Options{Mapper: passthrough}); !errors.Is(err, ErrUnsupportedOp) {
+		t.Fatalf("Incorrect error WANT: %v GOT: %v", ErrUnsupportedOp, err)
+	}
+
+	errCoerce := errors.New("coerce")
+
+	_, err := Filter(filter(qparams.Condition{Field: "age", Op: qparams.In, Values: []string{"1", "2"}}), Options{
+		Mapper: passthrough,
+		Coerce: func(string, any) (any, error) { return nil, errCoerce },
+	})
+
+	if !errors.Is(err, errCoerce) {
+		t.Fatalf("Incorrect error WANT: %v GOT: %v", errCoerce, err)
+	}
+
+	_, err = Filter(filter(cond("secret", qparams.Eq, "x")), Options{
+		Mapper: qparams.FieldMap{"name": "customer.name"},
+	})
+
+	if !errors.Is(err, ErrUnknownField) {
+		t.Fatalf("Incorrect error WANT: %v GOT: %v", ErrUnknownField, err)
+	}
+
+	_, err = Filter(filter(qparams.And{cond("name", qparams.Eq, "john"), cond("secret", qparams.Eq, "x")}), Options{
+		Mapper: qparams.FieldMapFunc(func(f string) (string, bool) { return "customer." + f, f != "secret" }),
+	})
+
+	if !errors.Is(err, ErrUnknownField) {
+		t.Fatalf("Incorrect error WANT: %v GOT: %v", ErrUnknownField, err)
+	}
+
+	_, err = Filter(filter(cond("name", qparams.Eq, "john")), Options{})
+	if !errors.Is(err, ErrUnknownField) {
+		t.Fatalf("Incorrect error WANT: %v GOT: %v", ErrUnknownField, err)
+	}
+}
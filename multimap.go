@@ -0,0 +1,88 @@
+package qparams
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// MultiMap represents qparams map[string][]string type, it is parsed
+// the same way as Map but keeps all values of repeated field and
+// operator pairs in order eg. tag==a,tag==b yields
+// MultiMap{"tag ==": {"a", "b"}}
+type MultiMap map[string][]string
+
+// MultiMap returns map[string][]string of qparams MultiMap
+func (m MultiMap) MultiMap() map[string][]string {
+	return map[string][]string(m)
+}
+
+// Values returns all values of field and operator pair eg.
+// m.Values("tag", "==")
+func (m MultiMap) Values(field, op string) []string {
+	return m[mapKey(field, op)]
+}
+
+// ToIntSlice will attempt to convert values of field and operator pair
+// to int slice. Will return error if it is unable to convert any value,
+// and a partial slice without errornous values
+func (m MultiMap) ToIntSlice(field, op string) ([]int, error) {
+	var err error
+
+	newSlice := []int{}
+
+	for _, v := range m.Values(field, op) {
+		i, e := strconv.Atoi(v)
+		if e != nil {
+			err = fmt.Errorf("Could not convert value %s of %s to int", v, mapKey(field, op))
+			continue
+		}
+
+		newSlice = append(newSlice, i)
+	}
+
+	return newSlice, err
+}
+
+// ToFloatSlice will attempt to convert values of field and operator pair
+// to float64 slice. Will return error if it is unable to convert any value,
+// and a partial slice without errornous values
+func (m MultiMap) ToFloatSlice(field, op string) ([]float64, error) {
+	var err error
+
+	newSlice := []float64{}
+
+	for _, v := range m.Values(field, op) {
+		f, e := strconv.ParseFloat(v, 64)
+		if e != nil {
+			err = fmt.Errorf("Could not convert value %s of %s to float", v, mapKey(field, op))
+			continue
+		}
+
+		newSlice = append(newSlice, f)
+	}
+
+	return newSlice, err
+}
+
+func (m *MultiMap) parseField(d *Decoder, sField reflect.StructField, queryValue string) error {
+	if err := d.checkMap(sField, queryValue); err != nil {
+		return err
+	}
+
+	parsed := MultiMap{}
+
+	walkFunc(queryValue, getSeparator(sField), getOperators(sField), func(key, value string) {
+		parsed[key] = append(parsed[key], value)
+	})
+
+	*m = parsed
+
+	return nil
+}
+
+// mapKey returns Map and MultiMap key of field and operator pair
+func mapKey(field, op string) string {
+	return fmt.Sprintf("%s %s", strings.ToLower(field), op)
+}
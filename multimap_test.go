@@ -0,0 +1,67 @@
+package qparams
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMultiMap(t *testing.T) {
+	type testStruct struct {
+		Filter MultiMap `qparams:"ops:==,>=,!="`
+		Tags   MultiMap `qparams:"sep:| ops:=="`
+	}
+
+	table := []testCase{
+		{
+			URL: "foobar.com?filter=tag==a,Tag==b,age>=7,tag!=c,age>=9&tags=x==1|x==2",
+			ExpectedResult: testStruct{
+				Filter: MultiMap{"tag ==": {"a", "b"}, "age >=": {"7", "9"}, "tag !=": {"c"}},
+				Tags:   MultiMap{"x ==": {"1", "2"}},
+			},
+			ExpectedError: nil,
+		},
+
+		{
+			URL:            "foobar.com?filter=",
+			ExpectedResult: testStruct{},
+			ExpectedError:  nil,
+		},
+	}
+
+	t.Log("")
+	t.Log("Testing multi map parsing")
+
+	for _, c := range table {
+		opts := testStruct{}
+		r := newRequest(c.URL)
+		err := Parse(&opts, r)
+
+		compare(t, c, opts, err)
+	}
+}
+
+func TestMultiMapHelpers(t *testing.T) {
+	m := MultiMap{"age >=": {"7", "x", "9"}, "score ==": {"1.5", "2"}}
+
+	if v := m.Values("Age", ">="); !reflect.DeepEqual(v, []string{"7", "x", "9"}) {
+		t.Fatalf("Incorrect values GOT: %v", v)
+	}
+
+	ints, err := m.ToIntSlice("age", ">=")
+	if err == nil || !reflect.DeepEqual(ints, []int{7, 9}) {
+		t.Fatalf("Incorrect int slice GOT: %v %v", ints, err)
+	}
+
+	floats, err := m.ToFloatSlice("score", "==")
+	if err != nil || !reflect.DeepEqual(floats, []float64{1.5, 2}) {
+		t.Fatalf("Incorrect float slice GOT: %v %v", floats, err)
+	}
+
+	if ints, err := m.ToIntSlice("missing", "=="); err != nil || len(ints) != 0 {
+		t.Fatalf("Incorrect int slice of missing key GOT: %v %v", ints, err)
+	}
+
+	if !reflect.DeepEqual(m.MultiMap(), map[string][]string(m)) {
+		t.Fatalf("Incorrect map GOT: %v", m.MultiMap())
+	}
+}
@@ -0,0 +1,151 @@
+package qparams
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Op represents canonical filter operator
+type Op int
+
+// Canonical filter operators. NotIn and NotNull are only used in
+// alias tables, parsed conditions carry In and IsNull with Not set
+const (
+	// Custom is an operator without canonical meaning,
+	// see Condition.RawOp
+	Custom Op = iota
+	Eq
+	Ne
+	Gt
+	Gte
+	Lt
+	Lte
+	In
+	NotIn
+	Between
+	IsNull
+	NotNull
+	Contains
+	StartsWith
+	EndsWith
+	Like
+	IContains
+	IStartsWith
+	IEndsWith
+	ILike
+)
+
+var opNames = []string{
+	"custom", "eq", "ne", "gt", "gte", "lt", "lte", "in", "notin", "between",
+	"isnull", "notnull", "contains", "startswith", "endswith", "like",
+	"icontains", "istartswith", "iendswith", "ilike",
+}
+
+func (o Op) String() string {
+	if o < 0 || int(o) >= len(opNames) {
+		return fmt.Sprintf("Op(%d)", int(o))
+	}
+
+	return opNames[o]
+}
+
+// ParseOp returns operator named name eg. gte
+func ParseOp(name string) (Op, bool) {
+	for i, n := range opNames {
+		if n == name {
+			return Op(i), true
+		}
+	}
+
+	return Custom, false
+}
+
+// DefaultAliases maps common operator tokens to canonical operators,
+// it is extended by Decoder.Aliases and alias field tag
+// eg. `qparams:"ops:=ge=,~ alias:~=contains"`
+var DefaultAliases = map[string]Op{
+	"==": Eq, "=": Eq, "eq": Eq, "=eq=": Eq,
+	"!=": Ne, "<>": Ne, "ne": Ne, "=ne=": Ne,
+	">": Gt, "gt": Gt, "=gt=": Gt,
+	">=": Gte, "gte": Gte, "ge": Gte, "=ge=": Gte, "=gte=": Gte,
+	"<": Lt, "lt": Lt, "=lt=": Lt,
+	"<=": Lte, "lte": Lte, "le": Lte, "=le=": Lte, "=lte=": Lte,
+	"in": In, "=in=": In,
+	"nin": NotIn, "notin": NotIn, "=out=": NotIn, "=nin=": NotIn,
+	"isnull": IsNull, "=isnull=": IsNull,
+	"notnull": NotNull, "=notnull=": NotNull,
+	"like": Like, "=like=": Like,
+	"ilike": ILike, "=ilike=": ILike,
+}
+
+// opTags lists tags declaring operators of a canonical kind
+// eg. `qparams:"inop:@"`
+var opTags = map[string]Op{
+	"inop":        In,
+	"notin":       NotIn,
+	"range":       Between,
+	"isnull":      IsNull,
+	"notnull":     NotNull,
+	"contains":    Contains,
+	"startswith":  StartsWith,
+	"endswith":    EndsWith,
+	"like":        Like,
+	"icontains":   IContains,
+	"istartswith": IStartsWith,
+	"iendswith":   IEndsWith,
+	"ilike":       ILike,
+}
+
+// normalize returns the operator NotIn and NotNull negate
+func (o Op) normalize() (op Op, not bool) {
+	switch o {
+	case NotIn:
+		return In, true
+	case NotNull:
+		return IsNull, true
+	}
+
+	return o, false
+}
+
+// match returns pattern match mode of the operator,
+// ok is false for operators that do not match patterns
+func (o Op) match() (mode MatchMode, fold bool, ok bool) {
+	switch o {
+	case Contains, IContains:
+		mode = MatchContains
+	case StartsWith, IStartsWith:
+		mode = MatchPrefix
+	case EndsWith, IEndsWith:
+		mode = MatchSuffix
+	case Like, ILike:
+		mode = MatchLike
+	default:
+		return 0, false, false
+	}
+
+	return mode, o >= IContains, true
+}
+
+// getAliases parses the alias tag eg. `qparams:"alias:~=contains,=ge==gte"`,
+// the last = separates the token from the operator name
+func getAliases(sField reflect.StructField) (map[string]Op, error) {
+	aliases := make(map[string]Op)
+
+	for _, a := range getTagList("alias", sField) {
+		i := strings.LastIndex(a, "=")
+		if i < 1 {
+			return nil, fmt.Errorf("Field %s has invalid operator alias %s", sField.Name, a)
+		}
+
+		op, ok := ParseOp(a[i+1:])
+		if !ok {
+			return nil, fmt.Errorf("Field %s has unknown operator %s", sField.Name, a[i+1:])
+		}
+
+		aliases[a[:i]] = op
+	}
+
+	return aliases, nil
+}
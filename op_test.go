@@ -0,0 +1,137 @@
+package qparams
+
+import (
+	"testing"
+)
+
+func TestFilterOperatorAliases(t *testing.T) {
+	type testStruct struct {
+		Filter Filter `qparams:"ops:>=,=gte=,=ge=,=out=,~ alias:~=icontains,=ge==gt"`
+	}
+
+	table := []testCase{
+		{
+			URL: "foobar.com?filter=a>=1,b=gte=2,c=ge=3,d=out=(x,y),e~Jo",
+			ExpectedResult: testStruct{Filter: Filter{Conditions: []Condition{
+				{Field: "a", Op: Gte, RawOp: ">=", Value: "1"},
+				{Field: "b", Op: Gte, RawOp: "=gte=", Value: "2"},
+				{Field: "c", Op: Gt, RawOp: "=ge=", Value: "3"},
+				{Field: "d", Op: In, RawOp: "=out=", Value: "(x,y)", Values: []string{"x", "y"}, Not: true},
+				{Field: "e", Op: IContains, RawOp: "~", Value: "Jo", Pattern: &Pattern{Mode: MatchContains, Parts: []string{"Jo"}, Fold: true}},
+			}}},
+			ExpectedError: nil,
+		},
+	}
+
+	t.Log("")
+	t.Log("Testing filter operator aliases")
+
+	for _, c := range table {
+		opts := testStruct{}
+		r := newRequest(c.URL)
+		err := Parse(&opts, r)
+
+		compare(t, c, opts, err)
+	}
+}
+
+func TestDecoderOperatorAliases(t *testing.T) {
+	type testStruct struct {
+		Filter Filter
+	}
+
+	d := &Decoder{Aliases: map[string]Op{"=gte=": Gte, ":": Eq}}
+
+	table := []testCase{
+		{
+			URL: "foobar.com?filter=a=gte=1,b:2,c<>3",
+			ExpectedResult: testStruct{Filter: Filter{Conditions: []Condition{
+				{Field: "a", Op: Gte, RawOp: "=gte=", Value: "1"},
+				{Field: "b", Op: Eq, RawOp: ":", Value: "2"},
+				{Field: "c", Op: Lt, RawOp: "<", Value: ">3"},
+			}}},
+			ExpectedError: nil,
+		},
+	}
+
+	t.Log("")
+	t.Log("Testing decoder operator aliases")
+
+	for _, c := range table {
+		opts := testStruct{}
+		r := newRequest(c.URL)
+		err := d.Parse(&opts, r)
+
+		compare(t, c, opts, err)
+	}
+}
+
+func TestWordOperatorAliases(t *testing.T) {
+	type testStruct struct {
+		Filter Filter `qparams:"ops:gte,ge,=="`
+	}
+
+	type decoderStruct struct {
+		Filter Filter
+	}
+
+	d := &Decoder{Aliases: map[string]Op{"eq": Eq}}
+
+	table := []struct {
+		d    *Decoder
+		dest interface{}
+		c    testCase
+	}{
+		{
+			defaultDecoder, &testStruct{},
+			testCase{
+				URL: "foobar.com?filter=age+gte+5,gender==m,age+ge+3,geo==x",
+				ExpectedResult: &testStruct{Filter: Filter{Conditions: []Condition{
+					{Field: "age", Op: Gte, RawOp: "gte", Value: "5"},
+					{Field: "gender", Op: Eq, RawOp: "==", Value: "m"},
+					{Field: "age", Op: Gte, RawOp: "ge", Value: "3"},
+					{Field: "geo", Op: Eq, RawOp: "==", Value: "x"},
+				}}},
+			},
+		},
+		{
+			defaultDecoder, &testStruct{},
+			testCase{
+				URL:            "foobar.com?filter=agegte5",
+				ExpectedResult: &testStruct{},
+				ExpectedError:  TypeConvErrors{"Field Filter contains invalid filter condition (agegte5)"},
+			},
+		},
+		{
+			d, &decoderStruct{},
+			testCase{
+				URL: "foobar.com?filter=frequency==1,speed+eq+2,req>eq",
+				ExpectedResult: &decoderStruct{Filter: Filter{Conditions: []Condition{
+					{Field: "frequency", Op: Eq, RawOp: "==", Value: "1"},
+					{Field: "speed", Op: Eq, RawOp: "eq", Value: "2"},
+					{Field: "req", Op: Gt, RawOp: ">", Value: "eq"},
+				}}},
+			},
+		},
+	}
+
+	t.Log("")
+	t.Log("Testing word operator aliases")
+
+	for _, c := range table {
+		err := c.d.Parse(c.dest, newRequest(c.c.URL))
+
+		compare(t, c.c, c.dest, err)
+	}
+}
+
+func TestParseOp(t *testing.T) {
+	for op := Custom; op <= ILike; op++ {
+		got, ok := ParseOp(op.String())
+		if !ok || got != op {
+			failFatal(t, "Test failed", op, got)
+		}
+	}
+
+	pass(t, "Test passed", ILike, ILike)
+}
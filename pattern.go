@@ -0,0 +1,177 @@
+package qparams
+
+import (
+	"regexp"
+	"strings"
+)
+
+// MatchMode represents the kind of pattern match
+type MatchMode int
+
+// Pattern match modes
+const (
+	MatchContains MatchMode = iota + 1
+	MatchPrefix
+	MatchSuffix
+	MatchLike
+)
+
+const (
+	wildcard       = '*'
+	wildcardEscape = '\\'
+)
+
+// Pattern represents the value of a pattern matching condition,
+// it never contains user wildcards, only literal text
+type Pattern struct {
+	Mode MatchMode
+
+	// Parts holds the literal text to match, for MatchLike mode
+	// it holds literal parts separated by * wildcards eg. Jo*n yields
+	// Jo and n, *Jo yields an empty part and Jo.
+	// Other modes hold a single part
+	Parts []string
+
+	// Fold reports whether matching is case-insensitive
+	Fold bool
+}
+
+// newPattern creates pattern from raw condition value, for MatchLike
+// mode * is a wildcard and \* or \\ escape literal * and \
+func newPattern(mode MatchMode, fold bool, value string) Pattern {
+	p := Pattern{Mode: mode, Fold: fold}
+
+	if mode != MatchLike {
+		p.Parts = []string{value}
+		return p
+	}
+
+	var part strings.Builder
+
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+
+		switch {
+		case c == wildcardEscape && i+1 < len(value) &&
+			(value[i+1] == wildcard || value[i+1] == wildcardEscape):
+			i++
+			part.WriteByte(value[i])
+		case c == wildcard:
+			p.Parts = append(p.Parts, part.String())
+			part.Reset()
+		default:
+			part.WriteByte(c)
+		}
+	}
+
+	p.Parts = append(p.Parts, part.String())
+
+	return p
+}
+
+// hasWildcard reports whether value contains an unescaped wildcard
+func hasWildcard(value string) bool {
+	return len(newPattern(MatchLike, false, value).Parts) > 1
+}
+
+// unescapeLike returns literal text of like value
+// without wildcards eg. Jo\* yields Jo*
+func unescapeLike(value string) string {
+	return strings.Join(newPattern(MatchLike, false, value).Parts, "*")
+}
+
+// like returns pattern parts as like parts, eg. contains x
+// yields "", "x", ""
+func (p Pattern) like() []string {
+	switch p.Mode {
+	case MatchContains:
+		return []string{"", p.Parts[0], ""}
+	case MatchPrefix:
+		return []string{p.Parts[0], ""}
+	case MatchSuffix:
+		return []string{"", p.Parts[0]}
+	}
+
+	return p.Parts
+}
+
+// SQLLike returns SQL LIKE pattern, literal %, _ and escape
+// characters are escaped with escape character
+func (p Pattern) SQLLike(escape rune) string {
+	e := string(escape)
+
+	r := strings.NewReplacer(e, e+e, "%", e+"%", "_", e+"_")
+
+	parts := make([]string, 0, len(p.Parts))
+	for _, part := range p.like() {
+		parts = append(parts, r.Replace(part))
+	}
+
+	return strings.Join(parts, "%")
+}
+
+// Wildcard returns wildcard pattern using * wildcards, literal *, ?
+// and \ are escaped with \ eg. for Elasticsearch wildcard queries
+func (p Pattern) Wildcard() string {
+	r := strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`)
+
+	parts := make([]string, 0, len(p.Parts))
+	for _, part := range p.like() {
+		parts = append(parts, r.Replace(part))
+	}
+
+	return strings.Join(parts, "*")
+}
+
+// Regexp returns anchored regular expression matching the pattern
+func (p Pattern) Regexp() string {
+	parts := make([]string, 0, len(p.Parts))
+	for _, part := range p.like() {
+		parts = append(parts, regexp.QuoteMeta(part))
+	}
+
+	expr := "^" + strings.Join(parts, ".*") + "$"
+
+	if p.Fold {
+		expr = "(?i)" + expr
+	}
+
+	return expr
+}
+
+// Match reports whether s matches the pattern, parts are matched
+// directly without compiling a regular expression
+func (p Pattern) Match(s string) bool {
+	parts := p.like()
+
+	if p.Fold {
+		s = strings.ToLower(s)
+
+		lowered := make([]string, len(parts))
+		for i, part := range parts {
+			lowered[i] = strings.ToLower(part)
+		}
+		parts = lowered
+	}
+
+	if len(parts) == 1 {
+		return s == parts[0]
+	}
+
+	if !strings.HasPrefix(s, parts[0]) {
+		return false
+	}
+
+	s = s[len(parts[0]):]
+
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(s, part)
+		if i == -1 {
+			return false
+		}
+
+		s = s[i+len(part):]
+	}
+
+	return strings.HasSuffix(s, parts[len(parts)-1])
+}
@@ -0,0 +1,132 @@
+package qparams
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestParseFilterPattern(t *testing.T) {
+	type testStruct struct {
+		Filter Filter `qparams:"ops:== contains:=contains= istartswith:=istarts= endswith:=ends= like:==,=like="`
+	}
+
+	table := []testCase{
+		{
+			URL: "foobar.com?filter=name==Jo*,name==John,name=contains=o_n,name=istarts=jo,name=ends=%25,name=like=a\\*b*c",
+			ExpectedResult: testStruct{Filter: Filter{Conditions: []Condition{
+				{Field: "name", Op: Like, RawOp: "==", Value: "Jo*", Pattern: &Pattern{Mode: MatchLike, Parts: []string{"Jo", ""}}},
+				{Field: "name", Op: Eq, RawOp: "==", Value: "John"},
+				{Field: "name", Op: Contains, RawOp: "=contains=", Value: "o_n", Pattern: &Pattern{Mode: MatchContains, Parts: []string{"o_n"}}},
+				{Field: "name", Op: IStartsWith, RawOp: "=istarts=", Value: "jo", Pattern: &Pattern{Mode: MatchPrefix, Parts: []string{"jo"}, Fold: true}},
+				{Field: "name", Op: EndsWith, RawOp: "=ends=", Value: "%", Pattern: &Pattern{Mode: MatchSuffix, Parts: []string{"%"}}},
+				{Field: "name", Op: Like, RawOp: "=like=", Value: "a\\*b*c", Pattern: &Pattern{Mode: MatchLike, Parts: []string{"a*b", "c"}}},
+			}}},
+			ExpectedError: nil,
+		},
+
+		{
+			URL: "foobar.com?filter=name==Jo\\*,name==a\\\\b,name=like=Jo\\*",
+			ExpectedResult: testStruct{Filter: Filter{Conditions: []Condition{
+				{Field: "name", Op: Eq, RawOp: "==", Value: "Jo*"},
+				{Field: "name", Op: Eq, RawOp: "==", Value: "a\\b"},
+				{Field: "name", Op: Like, RawOp: "=like=", Value: "Jo\\*", Pattern: &Pattern{Mode: MatchLike, Parts: []string{"Jo*"}}},
+			}}},
+			ExpectedError: nil,
+		},
+
+		{
+			URL:            "foobar.com?filter=name=contains=",
+			ExpectedResult: testStruct{},
+			ExpectedError:  TypeConvErrors{"Filter field name contains an empty pattern"},
+		},
+	}
+
+	t.Log("")
+	t.Log("Testing filter pattern parsing")
+
+	for _, c := range table {
+		opts := testStruct{}
+		r := newRequest(c.URL)
+		err := Parse(&opts, r)
+
+		compare(t, c, opts, err)
+	}
+}
+
+func TestPattern(t *testing.T) {
+	table := []struct {
+		pattern  Pattern
+		like     string
+		wildcard string
+		regexp   string
+		match    string
+		noMatch  string
+	}{
+		{
+			pattern:  Pattern{Mode: MatchLike, Parts: []string{"Jo", "n_%"}},
+			like:     `Jo%n\_\%`,
+			wildcard: `Jo*n_%`,
+			regexp:   `^Jo.*n_%$`,
+			match:    "John_%",
+			noMatch:  "john_%",
+		},
+		{
+			pattern:  Pattern{Mode: MatchContains, Parts: []string{`a\b`}, Fold: true},
+			like:     `%a\\b%`,
+			wildcard: `*a\\b*`,
+			regexp:   `(?i)^.*a\\b.*$`,
+			match:    `XA\By`,
+			noMatch:  `ab`,
+		},
+		{
+			pattern:  Pattern{Mode: MatchPrefix, Parts: []string{"a.b"}},
+			like:     `a.b%`,
+			wildcard: `a.b*`,
+			regexp:   `^a\.b.*$`,
+			match:    "a.bc",
+			noMatch:  "axbc",
+		},
+		{
+			pattern:  Pattern{Mode: MatchSuffix, Parts: []string{"x"}},
+			like:     `%x`,
+			wildcard: `*x`,
+			regexp:   `^.*x$`,
+			match:    "abx",
+			noMatch:  "xa",
+		},
+	}
+
+	t.Log("")
+	t.Log("Testing pattern conversion")
+
+	for _, c := range table {
+		got := []interface{}{c.pattern.SQLLike('\\'), c.pattern.Wildcard(), c.pattern.Regexp(), c.pattern.Match(c.match), c.pattern.Match(c.noMatch)}
+		want := []interface{}{c.like, c.wildcard, c.regexp, true, false}
+
+		compare(t, testCase{ExpectedResult: want}, got, nil)
+	}
+}
+
+func TestPatternMatch(t *testing.T) {
+	patterns := []Pattern{
+		{Mode: MatchLike, Parts: []string{"Jo", "n"}},
+		{Mode: MatchLike, Parts: []string{"", "a", "a", ""}},
+		{Mode: MatchLike, Parts: []string{"ab", "ab"}},
+		{Mode: MatchLike, Parts: []string{"x"}},
+		{Mode: MatchLike, Parts: []string{"", ""}},
+		{Mode: MatchContains, Parts: []string{"É"}, Fold: true},
+		{Mode: MatchPrefix, Parts: []string{"a\nb"}},
+	}
+
+	inputs := []string{"", "x", "John", "Jon", "Jo", "n", "aa", "a", "bab", "ab", "abab", "aab", "éa", "xÉy", "a\nbc", "a\nb", "a\n\nb"}
+
+	for _, p := range patterns {
+		re := regexp.MustCompile("(?s)" + p.Regexp())
+
+		for _, s := range inputs {
+			if got, want := p.Match(s), re.MatchString(s); got != want {
+				t.Fatalf("Incorrect match of %q with %+v WANT: %v GOT: %v", s, p, want, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package qparams
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// structPlan holds parsing metadata of a destination struct type,
+// it is built once per type and reused by all decoders
+type structPlan struct {
+	fields []fieldPlan
+}
+
+// fieldPlan holds parsing metadata of a single struct field
+type fieldPlan struct {
+	index  int
+	name   string
+	sField reflect.StructField
+
+	// in is the value source, one of sourceTags
+	in string
+
+	// def is the default value set with default tag
+	def string
+
+	// parser reports whether field type implements fieldParser
+	parser bool
+}
+
+// sourceTags lists values of in tag selecting field value source
+var sourceTags = map[string]bool{
+	"query":  true,
+	"header": true,
+	"cookie": true,
+	"path":   true,
+}
+
+// lookup returns raw field value from source of its in tag,
+// the first value is used if there are many
+func (f fieldPlan) lookup(sources map[string]ValueSource) string {
+	src, ok := sources[f.in]
+	if !ok {
+		return ""
+	}
+
+	values, ok := src.Lookup(f.name)
+	if !ok || len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+var (
+	plans sync.Map
+
+	fieldParserType = reflect.TypeOf((*fieldParser)(nil)).Elem()
+)
+
+// getPlan returns cached plan of struct type t, building it
+// on first use
+func getPlan(t reflect.Type) (*structPlan, error) {
+	if p, ok := plans.Load(t); ok {
+		return p.(*structPlan), nil
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil, ErrWrongDestType
+	}
+
+	plan := &structPlan{}
+
+	for i := 0; i < t.NumField(); i++ {
+		sField := t.Field(i)
+
+		name := strings.ToLower(sField.Name)
+		if tagName := getTag("name", sField); tagName != "" {
+			name = tagName
+		}
+
+		in := getTag("in", sField)
+		if in == "" {
+			in = "query"
+		}
+
+		if !sourceTags[in] {
+			return nil, fmt.Errorf("Field %s has invalid in tag %s", sField.Name, in)
+		}
+
+		if sField.PkgPath == "" {
+			if err := validateTags(sField); err != nil {
+				return nil, err
+			}
+		}
+
+		plan.fields = append(plan.fields, fieldPlan{
+			index:  i,
+			name:   name,
+			sField: sField,
+			in:     in,
+			def:    getTag("default", sField),
+			parser: sField.PkgPath == "" && reflect.PointerTo(sField.Type).Implements(fieldParserType),
+		})
+	}
+
+	p, _ := plans.LoadOrStore(t, plan)
+
+	return p.(*structPlan), nil
+}
+
+// validateTags checks limit tags of sField, and builds
+// filter tags if it is a Filter or FilterOf field
+func validateTags(sField reflect.StructField) error {
+	if _, err := getLimitTags(sField); err != nil {
+		return err
+	}
+
+	if reflect.PointerTo(sField.Type).Implements(filterFieldType) {
+		if _, err := getFilterTags(sField); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Prepare builds and caches parsing plan of T, it returns
+// ErrWrongDestType if T is not a struct, or an error describing
+// invalid field tags eg. unknown filter types. Calling it on startup
+// eg. in init catches misuse before the first request
+func Prepare[T any]() error {
+	_, err := getPlan(reflect.TypeOf((*T)(nil)).Elem())
+	return err
+}
+
+// ParseAs will try to parse query params from http.Request to
+// a new T, T must be a struct
+//
+//	params, err := qparams.ParseAs[MyParams](r)
+func ParseAs[T any](r *http.Request) (T, error) {
+	return ParseAsWith[T](defaultDecoder, r)
+}
+
+// ParseAsWith is ParseAs using decoder d
+func ParseAsWith[T any](d *Decoder, r *http.Request) (T, error) {
+	var dest T
+
+	if err := Prepare[T](); err != nil {
+		return dest, err
+	}
+
+	err := d.Parse(&dest, r)
+
+	return dest, err
+}
@@ -0,0 +1,144 @@
+package qparams
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestParseAs(t *testing.T) {
+	type testStruct struct {
+		Limit  int
+		Embed  Slice
+		Filter Filter
+		Name   string `qparams:"name:q"`
+	}
+
+	got, err := ParseAs[testStruct](newRequest("foobar.com?limit=10&embed=a,b&filter=age>=3&q=jo"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := testStruct{
+		Limit:  10,
+		Embed:  Slice{"a", "b"},
+		Filter: Filter{Conditions: []Condition{{Field: "age", Op: Gte, RawOp: ">=", Value: "3"}}},
+		Name:   "jo",
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Incorrect result WANT: %+v GOT: %+v", want, got)
+	}
+
+	got, err = ParseAs[testStruct](newRequest("foobar.com?limit=x"))
+	if err == nil || err.Error() != "Field Limit does not contain a valid integer (x)\n" {
+		t.Fatalf("Incorrect error GOT: %v", err)
+	}
+
+	d := &Decoder{Aliases: map[string]Op{"=gte=": Gte}}
+
+	got, err = ParseAsWith[testStruct](d, newRequest("foobar.com?filter=age=gte=3"))
+	if err != nil || got.Filter.Conditions[0].Op != Gte {
+		t.Fatalf("Incorrect decoder result GOT: %+v %v", got, err)
+	}
+}
+
+func TestParseAsMisuse(t *testing.T) {
+	type testStruct struct {
+		Limit int
+	}
+
+	if _, err := ParseAs[*testStruct](newRequest("foobar.com?limit=1")); !errors.Is(err, ErrWrongDestType) {
+		t.Fatalf("Incorrect error WANT: %v GOT: %v", ErrWrongDestType, err)
+	}
+
+	if err := Prepare[map[string]string](); !errors.Is(err, ErrWrongDestType) {
+		t.Fatalf("Incorrect error WANT: %v GOT: %v", ErrWrongDestType, err)
+	}
+
+	if err := Prepare[testStruct](); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	p1, _ := getPlan(reflect.TypeOf(testStruct{}))
+	p2, _ := getPlan(reflect.TypeOf(testStruct{}))
+
+	if p1 != p2 {
+		t.Fatalf("Plan should be cached")
+	}
+}
+
+func TestPrepareInvalidTags(t *testing.T) {
+	type badTypes struct {
+		Filter Filter `qparams:"types:x=nope"`
+	}
+
+	type badSchema struct {
+		Filter FilterOf[struct{ Tags []int }]
+	}
+
+	type badLimit struct {
+		Tags Map `qparams:"maxfields:x"`
+	}
+
+	type badExpr struct {
+		Filter Filter `qparams:"expr:maybe"`
+	}
+
+	table := []struct {
+		prepare func() error
+		err     string
+	}{
+		{Prepare[badTypes], "Unknown filter value type nope"},
+		{Prepare[badSchema], "Filter schema field Tags has unsupported type []int"},
+		{Prepare[badLimit], "Field Tags has invalid maxfields tag x"},
+		{Prepare[badExpr], "Field Filter has invalid expr tag maybe"},
+	}
+
+	for i, c := range table {
+		if err := c.prepare(); err == nil || err.Error() != c.err {
+			t.Fatalf("Incorrect error for case %d WANT: %s GOT: %v", i, c.err, err)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Handler should panic for invalid tags")
+		}
+	}()
+
+	Handler(func(w http.ResponseWriter, r *http.Request, p badTypes) {})
+}
+
+func TestFilterTagsCache(t *testing.T) {
+	type testStruct struct {
+		Filter Filter `qparams:"ops:== maxconditions:1"`
+	}
+
+	if err := Prepare[testStruct](); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	sField, _ := reflect.TypeOf(testStruct{}).FieldByName("Filter")
+
+	ft, err := getFilterTags(sField)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if cached, _ := getFilterTags(sField); cached != ft {
+		t.Fatal("Filter tags should be cached")
+	}
+
+	d := &Decoder{Limits: Limits{MaxConditions: 5, MaxFields: 2}, Aliases: map[string]Op{":": Eq}}
+
+	opts, err := d.filterOptions(sField)
+	if err != nil || opts.limits.MaxConditions != 1 || opts.limits.MaxFields != 2 || opts.aliases[":"] != Eq {
+		t.Fatalf("Incorrect decoder options GOT: %+v %v", opts, err)
+	}
+
+	if _, ok := ft.opts.aliases[":"]; ok {
+		t.Fatal("Decoder aliases should not modify cached options")
+	}
+}
@@ -0,0 +1,208 @@
+package qparams
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Media types written by ProblemRenderer
+const (
+	problemJSON = "application/problem+json"
+	plainJSON   = "application/json"
+)
+
+// Problem is an RFC 9457 problem details object
+type Problem struct {
+	Type          string         `json:"type,omitempty"`
+	Title         string         `json:"title,omitempty"`
+	Status        int            `json:"status,omitempty"`
+	Detail        string         `json:"detail,omitempty"`
+	Instance      string         `json:"instance,omitempty"`
+	InvalidParams []InvalidParam `json:"invalid-params,omitempty"`
+}
+
+// InvalidParam describes a single invalid param of Problem
+type InvalidParam struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+	Value  string `json:"value,omitempty"`
+}
+
+// ProblemRenderer writes errors as RFC 9457 application/problem+json
+// with invalid-params extension listing every *FieldError. The media
+// type is negotiated against the Accept header, application/json is
+// written to clients accepting only that and Fallback to clients
+// accepting neither
+type ProblemRenderer struct {
+	// Type is the problem type URI, about:blank if not set
+	Type string
+
+	// Title is the problem title, status text if not set
+	Title string
+
+	// ValidationType and ValidationTitle replace Type and Title
+	// for *ValidationError if set
+	ValidationType  string
+	ValidationTitle string
+
+	// Fallback writes errors which can not be negotiated,
+	// TextRenderer if nil
+	Fallback ErrorRenderer
+}
+
+// RenderError implements ErrorRenderer
+func (p *ProblemRenderer) RenderError(w http.ResponseWriter, r *http.Request, err error) {
+	ct := negotiateProblem(r.Header.Get("Accept"))
+	if ct == "" {
+		fallback := p.Fallback
+		if fallback == nil {
+			fallback = TextRenderer
+		}
+
+		fallback.RenderError(w, r, err)
+		return
+	}
+
+	problem := p.Problem(err)
+
+	w.Header().Set("Content-Type", ct)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(problem.Status)
+
+	json.NewEncoder(w).Encode(problem)
+}
+
+// Problem returns problem details of err
+func (p *ProblemRenderer) Problem(err error) Problem {
+	problem := Problem{
+		Type:   p.Type,
+		Title:  p.Title,
+		Status: errorStatus(err),
+	}
+
+	var vErr *ValidationError
+	if errors.As(err, &vErr) {
+		if p.ValidationType != "" {
+			problem.Type = p.ValidationType
+		}
+
+		if p.ValidationTitle != "" {
+			problem.Title = p.ValidationTitle
+		}
+	}
+
+	if problem.Type == "" {
+		problem.Type = "about:blank"
+	}
+
+	if problem.Title == "" {
+		problem.Title = http.StatusText(problem.Status)
+	}
+
+	var details []string
+
+	walkErrors(err, func(e error) {
+		var fe *FieldError
+		if !errors.As(e, &fe) {
+			details = append(details, e.Error())
+			return
+		}
+
+		name := fe.Param
+		if name == "" {
+			name = fe.Field
+		}
+
+		problem.InvalidParams = append(problem.InvalidParams, InvalidParam{
+			Name:   name,
+			Reason: fe.Reason,
+			Value:  fe.Value,
+		})
+	})
+
+	problem.Detail = strings.Join(details, "; ")
+
+	return problem
+}
+
+// walkErrors calls fn with every error joined in err
+func walkErrors(err error, fn func(error)) {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			walkErrors(e, fn)
+		}
+		return
+	}
+
+	if vErr, ok := err.(*ValidationError); ok {
+		walkErrors(vErr.Err, fn)
+		return
+	}
+
+	fn(err)
+}
+
+// negotiateProblem returns problem media type acceptable by accept
+// header value, empty string if there is none. Empty header accepts
+// problem+json. Media types are weighted by their most specific
+// range and q=0 marks them as not acceptable
+func negotiateProblem(accept string) string {
+	if strings.TrimSpace(accept) == "" {
+		return problemJSON
+	}
+
+	type weight struct {
+		q           float64
+		specificity int
+	}
+
+	weights := map[string]weight{}
+
+	for _, part := range strings.Split(accept, ",") {
+		mt, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+
+		for _, ct := range []string{problemJSON, plainJSON} {
+			var specificity int
+
+			switch mt {
+			case ct:
+				specificity = 3
+			case "application/*":
+				specificity = 2
+			case "*/*":
+				specificity = 1
+			default:
+				continue
+			}
+
+			if w, ok := weights[ct]; !ok || specificity > w.specificity {
+				weights[ct] = weight{q, specificity}
+			}
+		}
+	}
+
+	var best string
+	var bestQ float64
+
+	for _, ct := range []string{problemJSON, plainJSON} {
+		if q := weights[ct].q; q > bestQ {
+			best, bestQ = ct, q
+		}
+	}
+
+	return best
+}
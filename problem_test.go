@@ -0,0 +1,121 @@
+package qparams
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestNegotiateProblem(t *testing.T) {
+	table := []struct {
+		accept string
+		want   string
+	}{
+		{"", problemJSON},
+		{"*/*", problemJSON},
+		{"application/problem+json", problemJSON},
+		{"application/json", plainJSON},
+		{"application/json, application/problem+json", problemJSON},
+		{"application/json, application/problem+json;q=0.5", plainJSON},
+		{"text/html, application/*;q=0.2", problemJSON},
+		{"text/html", ""},
+		{"text/plain, application/json;q=0", ""},
+		{"application/json;q=x", ""},
+		{"application/problem+json;q=0", ""},
+		{"*/*;q=0", ""},
+		{"application/problem+json;q=0, */*", plainJSON},
+		{"application/*;q=0, application/json;q=0.1", plainJSON},
+	}
+
+	for _, c := range table {
+		if got := negotiateProblem(c.accept); got != c.want {
+			t.Fatalf("Incorrect media type for %q GOT: %q WANT: %q", c.accept, got, c.want)
+		}
+	}
+}
+
+func TestProblemRenderer(t *testing.T) {
+	type testStruct struct {
+		Limit int `qparams:"name:page_size"`
+		Page  int
+	}
+
+	h := HandlerWith(Options{Renderer: &ProblemRenderer{Type: "https://example.com/probs/invalid-params"}},
+		func(w http.ResponseWriter, r *http.Request, p testStruct) {})
+
+	r := newRequest("foobar.com?page_size=x&page=y")
+	r.Header.Set("Accept", "application/problem+json")
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusBadRequest || w.Header().Get("Content-Type") != problemJSON {
+		t.Fatalf("Incorrect response GOT: %d %s", w.Code, w.Header().Get("Content-Type"))
+	}
+
+	var got Problem
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got.Type != "https://example.com/probs/invalid-params" || got.Title != "Bad Request" || got.Status != 400 || got.Detail != "" {
+		t.Fatalf("Incorrect problem GOT: %+v", got)
+	}
+
+	if len(got.InvalidParams) != 2 || got.InvalidParams[0] != (InvalidParam{Name: "page_size", Reason: "Field Limit does not contain a valid integer (x)", Value: "x"}) ||
+		got.InvalidParams[1].Name != "page" {
+		t.Fatalf("Incorrect invalid params GOT: %+v", got.InvalidParams)
+	}
+
+	for _, accept := range []string{"text/html", "application/problem+json;q=0"} {
+		r.Header.Set("Accept", accept)
+
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		if w.Code != http.StatusBadRequest || w.Header().Get("Content-Type") != "text/plain; charset=utf-8" {
+			t.Fatalf("Incorrect fallback response for %q GOT: %d %s", accept, w.Code, w.Header().Get("Content-Type"))
+		}
+	}
+}
+
+func TestProblem(t *testing.T) {
+	p := &ProblemRenderer{Title: "Invalid request", ValidationType: "https://example.com/probs/validation", ValidationTitle: "Validation failed"}
+
+	table := []struct {
+		err  error
+		want Problem
+	}{
+		{
+			errors.New("Could not parse form body: broken"),
+			Problem{Type: "about:blank", Title: "Invalid request", Status: 400, Detail: "Could not parse form body: broken"},
+		},
+		{
+			&ValidationError{Err: Errors{&FieldError{Field: "From", Reason: "From must be before To"}, errors.New("Too many fields")}},
+			Problem{
+				Type:          "https://example.com/probs/validation",
+				Title:         "Validation failed",
+				Status:        400,
+				Detail:        "Too many fields",
+				InvalidParams: []InvalidParam{{Name: "From", Reason: "From must be before To"}},
+			},
+		},
+		{
+			Errors{&ElementError{FieldError: FieldError{Param: "ids", Field: "IDs", Value: "x", Reason: "bad"}, Index: 1}},
+			Problem{Type: "about:blank", Title: "Invalid request", Status: 400, InvalidParams: []InvalidParam{{Name: "ids", Reason: "bad", Value: "x"}}},
+		},
+		{
+			&http.MaxBytesError{Limit: 4},
+			Problem{Type: "about:blank", Title: "Invalid request", Status: 413, Detail: "http: request body too large"},
+		},
+	}
+
+	for i, c := range table {
+		if got := p.Problem(c.err); !reflect.DeepEqual(got, c.want) {
+			t.Fatalf("Incorrect problem for case %d GOT: %+v WANT: %+v", i, got, c.want)
+		}
+	}
+}
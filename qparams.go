@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"reflect"
 	"strconv"
 	"strings"
@@ -66,24 +67,46 @@ func (s *Slice) ToFloatSlice() ([]float64, error) {
 
 // ToIntAtIndex will attempt to convert i-th member of slice to integer
 // Will return conversion error on failure and an int zero value
-/*
 func (s *Slice) ToIntAtIndex(i int) (int, error) {
-	return 0, nil
+	if i < 0 || i >= len(*s) {
+		return 0, fmt.Errorf("Index %d out of range", i)
+	}
+
+	v, err := strconv.Atoi((*s)[i])
+	if err != nil {
+		return 0, fmt.Errorf("Could not convert member %s to int", (*s)[i])
+	}
+
+	return v, nil
 }
 
 // ToFloatAtIndex will attempt to convert i-th member of slice to float64
-// Will return conversion error on failure and an int zero value
+// Will return conversion error on failure and a float zero value
 func (s *Slice) ToFloatAtIndex(i int) (float64, error) {
-	return 0.0, nil
-}
-*/
+	if i < 0 || i >= len(*s) {
+		return 0.0, fmt.Errorf("Index %d out of range", i)
+	}
+
+	v, err := strconv.ParseFloat((*s)[i], 64)
+	if err != nil {
+		return 0.0, fmt.Errorf("Could not convert member %s to float", (*s)[i])
+	}
 
-// TODO - Add tofloat etc... and do the same for Map
+	return v, nil
+}
 
 // ErrWrongDestType is used when the provided dest is not struct pointer
 var ErrWrongDestType = errors.New("Dest must be a struct pointer")
 
+// ErrSemicolonQuery is returned by Parse for URL queries containing
+// an unencoded semicolon, which net/url drops together with its pair
+var ErrSemicolonQuery = errors.New("Query contains unencoded semicolon")
+
 // TypeConvErrors contain errors generated upon conversion to int or float64
+//
+// Deprecated: Parse returns Errors which carries typed FieldError values,
+// err.(TypeConvErrors) assertions on its result no longer match. See the
+// Upgrading section of the README for migration
 type TypeConvErrors []string
 
 func (e TypeConvErrors) Error() string {
@@ -96,49 +119,213 @@ func (e TypeConvErrors) Error() string {
 	return str
 }
 
+// Errors contains all errors generated while parsing query params,
+// conversion failures are reported as *FieldError
+type Errors []error
+
+func (e Errors) Error() string {
+	str := ""
+
+	for _, e := range e {
+		str += fmt.Sprintf("%s\n", e)
+	}
+
+	return str
+}
+
+// Unwrap returns all errors so they can be inspected with
+// errors.Is and errors.As
+func (e Errors) Unwrap() []error {
+	return e
+}
+
+// FieldError describes a query param value that could not be
+// converted to the type of its destination
+type FieldError struct {
+	// Param is the name of the query param
+	Param string
+
+	// Field is the name of the struct field, or the name of the
+	// filter field for filter conditions
+	Field string
+
+	// Value is the raw value that failed to convert
+	Value string
+
+	// Reason is a human readable description of the failure
+	Reason string
+
+	// Code identifies the kind of failure, empty for errors
+	// created outside of qparams
+	Code ErrorCode
+
+	// Args are values of Code message template besides
+	// field, param and value eg. type or max
+	Args map[string]string
+}
+
+func (e *FieldError) Error() string {
+	return e.Reason
+}
+
+// fieldParser is implemented by field types which parse the raw
+// query value on their own (eg. Filter)
+type fieldParser interface {
+	parseField(d *Decoder, sField reflect.StructField, queryValue string) error
+}
+
 var separator = ","
 var mapOpsTagSeparator = ","
 
+// Decoder parses query params using its configuration,
+// zero value Decoder parses the same way as Parse
+type Decoder struct {
+	// Aliases maps operator tokens to canonical filter operators,
+	// extending and overriding DefaultAliases. For filter fields
+	// without ops tag the tokens are valid operators
+	Aliases map[string]Op
+
+	// Limits restrict the size and complexity of Filter and Map
+	// params, exceeding a limit yields *LimitError
+	Limits Limits
+
+	// Source selects request values Parse reads, URL query
+	// by default
+	Source Source
+
+	// MaxBodySize limits the size of form bodies read by Parse,
+	// 10MB if not set
+	MaxBodySize int64
+
+	// Sources are consulted in order for query fields missing from
+	// parsed values eg. saved user preferences and tenant defaults
+	Sources []ValueSource
+
+	// Catalog localizes FieldError reasons returned by Parse to
+	// the language of the request, see Languages
+	Catalog MessageCatalog
+}
+
+var defaultDecoder = &Decoder{}
+
 // Parse will try to parse query params from http.Request to
 // provided struct, and will return error on filure
 func Parse(dest interface{}, r *http.Request) error {
-	var errs TypeConvErrors
+	return defaultDecoder.Parse(dest, r)
+}
+
+// ParseValues will try to parse query params from url.Values to
+// provided struct, and will return error on filure
+func ParseValues(dest interface{}, values url.Values) error {
+	return defaultDecoder.ParseValues(dest, values)
+}
+
+// ParseQuery will try to parse raw query string eg. limit=10&sort=-id
+// to provided struct, and will return error on filure. Malformed
+// pairs are skipped the same way as in http.Request URL query
+func ParseQuery(dest interface{}, query string) error {
+	return defaultDecoder.ParseQuery(dest, query)
+}
+
+// ParseURL will try to parse query params from url.URL to
+// provided struct, and will return error on filure
+func ParseURL(dest interface{}, u *url.URL) error {
+	return defaultDecoder.ParseURL(dest, u)
+}
+
+// Parse will try to parse query params, or form values depending on
+// decoder Source, from http.Request to provided struct, and will
+// return error on filure. URL queries with unencoded semicolons
+// yield ErrSemicolonQuery
+func (d *Decoder) Parse(dest interface{}, r *http.Request) error {
+	if strings.Contains(r.URL.RawQuery, ";") {
+		return ErrSemicolonQuery
+	}
+
+	src, err := d.RequestSource(r)
+	if err != nil {
+		return err
+	}
+
+	err = d.decode(dest, map[string]ValueSource{
+		"query":  src,
+		"header": HeaderSource(r),
+		"cookie": CookieSource(r),
+		"path":   PathSource(r),
+	})
+
+	if err != nil && d.Catalog != nil {
+		localizeErrors(err, d.Catalog, Languages(r))
+	}
+
+	return err
+}
+
+// ParseURL will try to parse query params from url.URL to
+// provided struct, and will return error on filure
+func (d *Decoder) ParseURL(dest interface{}, u *url.URL) error {
+	return d.ParseValues(dest, u.Query())
+}
+
+// ParseQuery will try to parse raw query string to provided struct,
+// and will return error on filure. Leading ? is optional
+func (d *Decoder) ParseQuery(dest interface{}, query string) error {
+	values, _ := url.ParseQuery(strings.TrimPrefix(query, "?"))
+
+	return d.ParseValues(dest, values)
+}
+
+// ParseValues will try to parse query params from url.Values to
+// provided struct, and will return error on filure.
+// Values are not modified, fields with header, cookie or path
+// source are left empty or set to their default value
+func (d *Decoder) ParseValues(dest interface{}, values url.Values) error {
+	return d.decode(dest, map[string]ValueSource{"query": d.chain(ValuesSource(values))})
+}
+
+// decode parses values of sources, keyed by in tag, to dest
+func (d *Decoder) decode(dest interface{}, sources map[string]ValueSource) error {
+	var errs Errors
 
 	t := reflect.TypeOf(dest)
 	v := reflect.ValueOf(dest)
-	queryValues := r.URL.Query()
 
-	if t.Kind() != reflect.Ptr ||
-		t.Elem().Kind() != reflect.Struct {
+	if t.Kind() != reflect.Ptr {
 		return ErrWrongDestType
 	}
 
-	// TODO - Cache struct meta data
+	plan, err := getPlan(t.Elem())
+	if err != nil {
+		return err
+	}
 
-	for i := 0; i < v.Elem().NumField(); i++ {
-		fieldT := t.Elem().Field(i)
-		fieldV := v.Elem().Field(i)
+	for _, f := range plan.fields {
+		fieldT := f.sField
+		fieldV := v.Elem().Field(f.index)
+		fieldName := f.name
 
-		fieldName := strings.ToLower(fieldT.Name)
+		queryValue := f.lookup(sources)
 
-		if tagFieldName := getTag("name", fieldT); tagFieldName != "" {
-			fieldName = tagFieldName
+		if queryValue == "" {
+			queryValue = f.def
 		}
 
-		for key, val := range queryValues {
-			key = strings.ToLower(key)
-			queryValues[key] = val
+		if queryValue == "" {
+			continue
 		}
 
-		queryValue := queryValues.Get(fieldName)
-
-		if queryValue == "" {
-			// TODO - Set default value here
+		if f.parser {
+			err := fieldV.Addr().Interface().(fieldParser).parseField(d, fieldT, queryValue)
+			errs = appendErrors(errs, err, fieldName)
 			continue
 		}
 
 		switch fieldT.Type.Name() {
 		case "Map":
+			if err := d.checkMap(fieldT, queryValue); err != nil {
+				errs = appendErrors(errs, err, fieldName)
+				continue
+			}
 			parseMap(fieldT, fieldV, queryValue)
 		case "Slice":
 			parseSlice(fieldT, fieldV, queryValue)
@@ -147,14 +334,10 @@ func Parse(dest interface{}, r *http.Request) error {
 		switch fieldV.Kind() {
 		case reflect.Int:
 			err := parseInt(fieldT, fieldV, queryValue)
-			if err != nil {
-				errs = append(errs, err.Error())
-			}
+			errs = appendErrors(errs, err, fieldName)
 		case reflect.Float64:
 			err := parseFloat64(fieldT, fieldV, queryValue)
-			if err != nil {
-				errs = append(errs, err.Error())
-			}
+			errs = appendErrors(errs, err, fieldName)
 		case reflect.String:
 			parseString(fieldT, fieldV, queryValue)
 		}
@@ -167,6 +350,30 @@ func Parse(dest interface{}, r *http.Request) error {
 	return nil
 }
 
+// appendErrors appends err (or all of its members if it is Errors) to
+// errs, setting the query param name on field errors
+func appendErrors(errs Errors, err error, param string) Errors {
+	if err == nil {
+		return errs
+	}
+
+	list, ok := err.(Errors)
+	if !ok {
+		list = Errors{err}
+	}
+
+	for _, e := range list {
+		var fe *FieldError
+		if errors.As(e, &fe) && fe.Param == "" {
+			fe.Param = param
+		}
+
+		errs = append(errs, e)
+	}
+
+	return errs
+}
+
 func getTag(tag string, sField reflect.StructField) string {
 	tags := sField.Tag.Get("qparams")
 
@@ -177,7 +384,7 @@ func getTag(tag string, sField reflect.StructField) string {
 	tagSlice := strings.Split(tags, " ")
 
 	for _, t := range tagSlice {
-		subSlice := strings.Split(t, ":")
+		subSlice := strings.SplitN(t, ":", 2)
 
 		if subSlice != nil &&
 			len(subSlice) == 2 &&
@@ -200,13 +407,18 @@ func getSeparator(sField reflect.StructField) string {
 }
 
 func getOperators(sField reflect.StructField) []string {
-	operators := []string{}
+	return getTagList("ops", sField)
+}
+
+// getTagList returns tag value split on mapOpsTagSeparator
+func getTagList(tag string, sField reflect.StructField) []string {
+	list := []string{}
 
-	if ops := getTag("ops", sField); ops != "" {
-		operators = strings.Split(ops, mapOpsTagSeparator)
+	if t := getTag(tag, sField); t != "" {
+		list = strings.Split(t, mapOpsTagSeparator)
 	}
 
-	return operators
+	return list
 }
 
 func parseMap(sField reflect.StructField, fieldV reflect.Value, queryValue string) {
@@ -241,7 +453,7 @@ func parseSlice(sField reflect.StructField, fieldV reflect.Value, queryValue str
 func parseInt(sField reflect.StructField, fieldV reflect.Value, queryValue string) error {
 	i, err := strconv.Atoi(queryValue)
 	if err != nil {
-		return fmt.Errorf("Field %s does not contain a valid integer (%s)", sField.Name, queryValue)
+		return newFieldError(CodeInvalidInt, sField.Name, queryValue, nil)
 	}
 
 	fieldV.Set(reflect.ValueOf(i))
@@ -252,7 +464,7 @@ func parseInt(sField reflect.StructField, fieldV reflect.Value, queryValue strin
 func parseFloat64(sField reflect.StructField, fieldV reflect.Value, queryValue string) error {
 	f, err := strconv.ParseFloat(queryValue, 64)
 	if err != nil {
-		return fmt.Errorf("Field %s does not contain a valid float (%s)", sField.Name, queryValue)
+		return newFieldError(CodeInvalidFloat, sField.Name, queryValue, nil)
 	}
 
 	fieldV.Set(reflect.ValueOf(f))
@@ -261,5 +473,5 @@ func parseFloat64(sField reflect.StructField, fieldV reflect.Value, queryValue s
 }
 
 func parseString(sField reflect.StructField, fieldV reflect.Value, queryValue string) {
-	fieldV.Set(reflect.ValueOf(queryValue))
+	fieldV.SetString(queryValue)
 }
@@ -497,6 +497,37 @@ func TestParseFloatErrors(t *testing.T) {
 	}
 }
 
+func TestParseDecimal(t *testing.T) {
+	type testStruct struct {
+		Amount Decimal
+	}
+
+	table := []testCase{
+		{
+			URL:            "foobar.com?amount=1.50",
+			ExpectedResult: testStruct{Amount: "1.50"},
+			ExpectedError:  nil,
+		},
+
+		{
+			URL:            "foobar.com?amount=1.5e3",
+			ExpectedResult: testStruct{},
+			ExpectedError:  TypeConvErrors{"Field Amount does not contain a valid decimal (1.5e3)"},
+		},
+	}
+
+	t.Log("")
+	t.Log("Testing decimal parsing")
+
+	for _, c := range table {
+		opts := testStruct{}
+		r := newRequest(c.URL)
+		err := Parse(&opts, r)
+
+		compare(t, c, opts, err)
+	}
+}
+
 func TestParseString(t *testing.T) {
 	type testStruct struct {
 		Name string
@@ -633,6 +664,64 @@ func TestIntSliceConvert(t *testing.T) {
 	}
 }
 
+func TestAtIndexConvert(t *testing.T) {
+	type testStruct struct {
+		IDs Slice
+	}
+
+	table := []testCase{
+		{
+			URL:                 "foobar.com?ids=1,2,3.5,7",
+			ExpectedResult:      testStruct{IDs: Slice{"1", "2", "3.5", "7"}},
+			ExpectedError:       nil,
+			ExpectedIntResult:   2,
+			ExpectedFloatResult: 2,
+			ExpectedConvErr:     nil,
+		},
+
+		{
+			URL:                 "foobar.com?ids=1,2a,3",
+			ExpectedResult:      testStruct{IDs: Slice{"1", "2a", "3"}},
+			ExpectedError:       nil,
+			ExpectedIntResult:   0,
+			ExpectedFloatResult: 0,
+			ExpectedConvErr:     fmt.Errorf("Could not convert member 2a to int"),
+		},
+	}
+
+	t.Log("")
+	t.Log("Testing conversion of slice member at index")
+
+	for _, c := range table {
+		opts := testStruct{}
+		r := newRequest(c.URL)
+		err := Parse(&opts, r)
+
+		compare(t, c, opts, err)
+
+		i, err := opts.IDs.ToIntAtIndex(1)
+		checkErr(t, err, c.ExpectedConvErr)
+
+		if i != c.ExpectedIntResult {
+			failFatal(t, "Incorrect int member", c.ExpectedIntResult, i)
+		}
+
+		f, err := opts.IDs.ToFloatAtIndex(1)
+		if (err == nil) != (c.ExpectedConvErr == nil) || f != c.ExpectedFloatResult {
+			failFatal(t, "Incorrect float member", c.ExpectedFloatResult, f, err)
+		}
+	}
+
+	s := Slice{"1"}
+	if _, err := s.ToIntAtIndex(1); err == nil || err.Error() != "Index 1 out of range" {
+		failFatal(t, "Incorrect out of range error", "Index 1 out of range", err)
+	}
+
+	if _, err := s.ToFloatAtIndex(-1); err == nil {
+		failFatal(t, "Expected out of range error", nil, err)
+	}
+}
+
 func TestFloatSliceConvert(t *testing.T) {
 	type testStruct struct {
 		IDs Slice
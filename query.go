@@ -0,0 +1,13 @@
+package qparams
+
+// Query groups parsed filter, sort and pagination params,
+// it is the input of backend translators eg. sqlfilter
+type Query struct {
+	Filter Filter
+	Sort   Sort
+
+	// Limit is the maximum number of results, 0 means no limit
+	Limit int
+
+	Offset int
+}
@@ -0,0 +1,146 @@
+package qparams
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rangeSeparator separates lower and upper bound eg. 10..100
+const rangeSeparator = ".."
+
+// Bounds holds the raw bounds of a range condition eg. amount=10..100,
+// an empty bound means the range is open on that side eg. 10..
+type Bounds struct {
+	Lower, Upper string
+}
+
+// RangeBound lists types usable as Range bounds
+type RangeBound interface {
+	int | int64 | float64 | Decimal | time.Time
+}
+
+// Range represents a range query param eg. price=10..100
+// Half open ranges 10.. and ..100 leave the missing bound nil.
+// Time bounds are parsed with RFC3339 layout unless
+// set with layout tag eg. `qparams:"layout:2006-01-02"`
+type Range[T RangeBound] struct {
+	Lower, Upper *T
+}
+
+// Contains reports whether v is within the range, bounds inclusive
+func (r Range[T]) Contains(v T) bool {
+	if r.Lower != nil {
+		if c, _ := compareValues(normalizeBound(*r.Lower), normalizeBound(v)); c > 0 {
+			return false
+		}
+	}
+
+	if r.Upper != nil {
+		if c, _ := compareValues(normalizeBound(v), normalizeBound(*r.Upper)); c > 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (r *Range[T]) parseField(d *Decoder, sField reflect.StructField, queryValue string) error {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+
+	vt, _ := fieldValueType(reflect.StructField{Name: sField.Name, Type: t, Tag: sField.Tag})
+
+	b, ok := splitRange(queryValue)
+	if !ok {
+		return newFieldError(CodeInvalidRange, sField.Name, queryValue, nil)
+	}
+
+	lower, upper, err := b.convert(vt)
+	if err != nil {
+		return newFieldError(CodeInvalidTypedRange, sField.Name, queryValue, map[string]string{"type": vt.String()})
+	}
+
+	if !boundsOrdered(lower, upper) {
+		return newFieldError(CodeRangeOrder, sField.Name, queryValue, nil)
+	}
+
+	r.Lower, r.Upper = toBound[T](lower, t), toBound[T](upper, t)
+
+	return nil
+}
+
+func toBound[T RangeBound](v interface{}, t reflect.Type) *T {
+	if v == nil {
+		return nil
+	}
+
+	b := reflect.ValueOf(v).Convert(t).Interface().(T)
+
+	return &b
+}
+
+// normalizeBound converts bound to the type produced by valueType
+// conversion so it can be compared with compareValues
+func normalizeBound(v interface{}) interface{} {
+	if i, ok := v.(int64); ok {
+		return int(i)
+	}
+
+	return v
+}
+
+// splitRange splits value eg. 10..100 on range separator,
+// ok is false if value is not a range or both bounds are missing
+func splitRange(value string) (Bounds, bool) {
+	i := strings.Index(value, rangeSeparator)
+	if i == -1 {
+		return Bounds{}, false
+	}
+
+	b := Bounds{
+		Lower: value[:i],
+		Upper: value[i+len(rangeSeparator):],
+	}
+
+	return b, b.Lower != "" || b.Upper != ""
+}
+
+// convert converts present bounds to vt, missing bounds are nil
+func (b Bounds) convert(vt valueType) (lower, upper interface{}, err error) {
+	if b.Lower != "" {
+		if lower, err = vt.convert(b.Lower); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if b.Upper != "" {
+		if upper, err = vt.convert(b.Upper); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return lower, upper, nil
+}
+
+// boundsOrdered reports whether lower is not greater than upper,
+// untyped bounds are only checked if both are numbers
+func boundsOrdered(lower, upper interface{}) bool {
+	if lower == nil || upper == nil {
+		return true
+	}
+
+	if l, ok := lower.(string); ok {
+		lf, errL := strconv.ParseFloat(l, 64)
+		uf, errU := strconv.ParseFloat(upper.(string), 64)
+		if errL != nil || errU != nil {
+			return true
+		}
+
+		lower, upper = lf, uf
+	}
+
+	c, ok := compareValues(lower, upper)
+
+	return !ok || c <= 0
+}
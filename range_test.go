@@ -0,0 +1,104 @@
+package qparams
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseFilterRange(t *testing.T) {
+	type testStruct struct {
+		Filter Filter `qparams:"range:= types:amount=float"`
+	}
+
+	table := []testCase{
+		{
+			URL: "foobar.com?filter=amount=10..100.5,age=18..,name=a..c,amount==7",
+			ExpectedResult: testStruct{Filter: Filter{Conditions: []Condition{
+				{Field: "amount", Op: Between, RawOp: "=", Value: "10..100.5", Range: &Bounds{"10", "100.5"}, Typed: [2]interface{}{10.0, 100.5}},
+				{Field: "age", Op: Between, RawOp: "=", Value: "18..", Range: &Bounds{"18", ""}},
+				{Field: "name", Op: Between, RawOp: "=", Value: "a..c", Range: &Bounds{"a", "c"}},
+				{Field: "amount", Op: Eq, RawOp: "==", Value: "7", Typed: 7.0},
+			}}},
+			ExpectedError: nil,
+		},
+
+		{
+			URL: "foobar.com?filter=amount=..100,age=7",
+			ExpectedResult: testStruct{Filter: Filter{Conditions: []Condition{
+				{Field: "amount", Op: Between, RawOp: "=", Value: "..100", Range: &Bounds{"", "100"}, Typed: [2]interface{}{nil, 100.0}},
+				{Field: "age", Op: Eq, RawOp: "=", Value: "7"},
+			}}},
+			ExpectedError: nil,
+		},
+
+		{
+			URL:            "foobar.com?filter=amount=100..10,age=30..4,size=..,amount=1..x",
+			ExpectedResult: testStruct{},
+			ExpectedError: TypeConvErrors{
+				"Filter field amount range lower bound is greater than upper bound (100..10)",
+				"Filter field age range lower bound is greater than upper bound (30..4)",
+				"Filter field size contains an invalid range (..)",
+				"Filter field amount does not contain a valid float (1..x)",
+			},
+		},
+	}
+
+	t.Log("")
+	t.Log("Testing filter range parsing")
+
+	for _, c := range table {
+		opts := testStruct{}
+		r := newRequest(c.URL)
+		err := Parse(&opts, r)
+
+		compare(t, c, opts, err)
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	type testStruct struct {
+		Price   Range[float64]
+		Count   Range[int64]
+		Created Range[time.Time] `qparams:"layout:2006-01-02"`
+	}
+
+	price, count := 10.5, int64(3)
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	table := []testCase{
+		{
+			URL: "foobar.com?price=..10.5&count=3..&created=2024-01-02..2024-01-02",
+			ExpectedResult: testStruct{
+				Price:   Range[float64]{Upper: &price},
+				Count:   Range[int64]{Lower: &count},
+				Created: Range[time.Time]{Lower: &day, Upper: &day},
+			},
+			ExpectedError: nil,
+		},
+
+		{
+			URL:            "foobar.com?price=10&count=5..1&created=2024..",
+			ExpectedResult: testStruct{},
+			ExpectedError: TypeConvErrors{
+				"Field Price does not contain a valid range (10)",
+				"Field Count range lower bound is greater than upper bound (5..1)",
+				"Field Created does not contain a valid time (2006-01-02) range (2024..)",
+			},
+		},
+	}
+
+	t.Log("")
+	t.Log("Testing range parsing")
+
+	for _, c := range table {
+		opts := testStruct{}
+		r := newRequest(c.URL)
+		err := Parse(&opts, r)
+
+		compare(t, c, opts, err)
+	}
+
+	if !(Range[int]{}).Contains(7) || (Range[int64]{Lower: &count}).Contains(2) {
+		failFatal(t, "Range contains failed", true, false)
+	}
+}
@@ -0,0 +1,126 @@
+package qparams
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SliceElem lists types usable as SliceOf elements
+type SliceElem interface {
+	string | int | int64 | float64 | bool | Decimal | time.Time
+}
+
+// SliceOf represents a typed slice query param eg. ids=1,2,3
+// Members are converted and validated while parsing, every invalid
+// member is reported as *ElementError and the field is left empty.
+// Time members are parsed with RFC3339 layout unless set with layout
+// tag, string members can be restricted with enum tag eg.
+// `qparams:"sep:| enum:paid|refunded"`
+type SliceOf[T SliceElem] []T
+
+// ElementError describes a SliceOf member that could not be
+// converted, it unwraps to the FieldError describing the failure
+type ElementError struct {
+	FieldError
+
+	// Index is the position of the member in the query param value
+	Index int
+}
+
+// Unwrap returns the underlying FieldError
+func (e *ElementError) Unwrap() error {
+	return &e.FieldError
+}
+
+// Slice returns []T of qparams SliceOf
+func (s SliceOf[T]) Slice() []T {
+	return []T(s)
+}
+
+// At returns i-th member of slice, ok is false if i is out of range
+func (s SliceOf[T]) At(i int) (v T, ok bool) {
+	if i < 0 || i >= len(s) {
+		return v, false
+	}
+
+	return s[i], true
+}
+
+// Contains reports whether slice contains v
+func (s SliceOf[T]) Contains(v T) bool {
+	for _, m := range s {
+		if c, ok := compareValues(normalizeBound(m), normalizeBound(v)); ok && c == 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Dedupe returns a copy of slice without repeated members,
+// keeping the first occurrence of each
+func (s SliceOf[T]) Dedupe() SliceOf[T] {
+	deduped := SliceOf[T]{}
+
+	for _, m := range s {
+		if !deduped.Contains(m) {
+			deduped = append(deduped, m)
+		}
+	}
+
+	return deduped
+}
+
+// Sorted returns a copy of slice sorted in ascending order,
+// false sorts before true
+func (s SliceOf[T]) Sorted() SliceOf[T] {
+	sorted := append(SliceOf[T]{}, s...)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		c, _ := compareValues(normalizeBound(sorted[i]), normalizeBound(sorted[j]))
+		return c < 0
+	})
+
+	return sorted
+}
+
+func (s *SliceOf[T]) parseField(d *Decoder, sField reflect.StructField, queryValue string) error {
+	var errs Errors
+
+	t := reflect.TypeOf((*T)(nil)).Elem()
+
+	vt, _ := fieldValueType(reflect.StructField{Name: sField.Name, Type: t, Tag: sField.Tag})
+
+	slice := SliceOf[T]{}
+
+	for i, m := range strings.Split(queryValue, getSeparator(sField)) {
+		if m == "" {
+			continue
+		}
+
+		v, err := vt.convert(m)
+		if err != nil {
+			errs = append(errs, &ElementError{
+				FieldError: *newFieldError(CodeInvalidElement, sField.Name, m, map[string]string{
+					"index": strconv.Itoa(i),
+					"type":  vt.String(),
+				}),
+				Index: i,
+			})
+			continue
+		}
+
+		slice = append(slice, reflect.ValueOf(v).Convert(t).Interface().(T))
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	*s = slice
+
+	return nil
+}
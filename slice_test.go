@@ -0,0 +1,107 @@
+package qparams
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseSliceOf(t *testing.T) {
+	type testStruct struct {
+		IDs    SliceOf[int]
+		Prices SliceOf[Decimal]   `qparams:"sep:|"`
+		Days   SliceOf[time.Time] `qparams:"layout:2006-01-02"`
+		Status SliceOf[string]    `qparams:"enum:paid|refunded"`
+		Flags  SliceOf[bool]
+	}
+
+	table := []testCase{
+		{
+			URL: "foobar.com?ids=,3,1,3,&prices=1.5|2&days=2024-01-02&status=paid,Refunded&flags=true,0",
+			ExpectedResult: testStruct{
+				IDs:    SliceOf[int]{3, 1, 3},
+				Prices: SliceOf[Decimal]{"1.5", "2"},
+				Days:   SliceOf[time.Time]{time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+				Flags:  SliceOf[bool]{true, false},
+			},
+			ExpectedError: TypeConvErrors{
+				"Field Status member 1 does not contain a valid value, one of paid, refunded (Refunded)",
+			},
+		},
+
+		{
+			URL:            "foobar.com?ids=1,x,3,4.5&prices=1|a",
+			ExpectedResult: testStruct{},
+			ExpectedError: TypeConvErrors{
+				"Field IDs member 1 does not contain a valid int (x)",
+				"Field IDs member 3 does not contain a valid int (4.5)",
+				"Field Prices member 1 does not contain a valid decimal (a)",
+			},
+		},
+	}
+
+	t.Log("")
+	t.Log("Testing typed slice parsing")
+
+	for _, c := range table {
+		opts := testStruct{}
+		r := newRequest(c.URL)
+		err := Parse(&opts, r)
+
+		compare(t, c, opts, err)
+	}
+
+	opts := testStruct{}
+	err := Parse(&opts, newRequest("foobar.com?ids=1,x"))
+
+	var ee *ElementError
+	if !errors.As(err, &ee) || ee.Index != 1 || ee.Param != "ids" || ee.Value != "x" {
+		t.Fatalf("Incorrect element error GOT: %#v", err)
+	}
+}
+
+func TestSliceOfHelpers(t *testing.T) {
+	ids := SliceOf[int]{3, 1, 3, 2}
+
+	if v, ok := ids.At(2); !ok || v != 3 {
+		t.Fatalf("Incorrect member GOT: %v %v", v, ok)
+	}
+
+	if _, ok := ids.At(4); ok {
+		t.Fatalf("Expected out of range member")
+	}
+
+	if !ids.Contains(2) || ids.Contains(5) {
+		t.Fatalf("Incorrect contains")
+	}
+
+	if d := ids.Dedupe(); !reflect.DeepEqual(d, SliceOf[int]{3, 1, 2}) {
+		t.Fatalf("Incorrect dedupe GOT: %v", d)
+	}
+
+	if s := ids.Sorted(); !reflect.DeepEqual(s, SliceOf[int]{1, 2, 3, 3}) || ids[0] != 3 {
+		t.Fatalf("Incorrect sort GOT: %v", s)
+	}
+
+	prices := SliceOf[Decimal]{"10", "9.5", "10.0"}
+
+	if d := prices.Dedupe(); !reflect.DeepEqual(d, SliceOf[Decimal]{"10", "9.5"}) {
+		t.Fatalf("Incorrect decimal dedupe GOT: %v", d)
+	}
+
+	if s := prices.Sorted(); !reflect.DeepEqual(s.Slice(), []Decimal{"9.5", "10", "10.0"}) {
+		t.Fatalf("Incorrect decimal sort GOT: %v", s)
+	}
+
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	days := SliceOf[time.Time]{day.Add(time.Hour), day}
+
+	if !days.Contains(day.In(time.FixedZone("X", 3600))) {
+		t.Fatalf("Incorrect time contains")
+	}
+
+	if s := days.Sorted(); !s[0].Equal(day) {
+		t.Fatalf("Incorrect time sort GOT: %v", s)
+	}
+}
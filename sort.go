@@ -0,0 +1,57 @@
+package qparams
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Sort represents a parsed sort query param eg. sort=-created,name
+// where - prefix means descending and optional + prefix ascending order
+type Sort []SortField
+
+// SortField represents a single sort field
+type SortField struct {
+	// Field is the lowercased name of the sorted field
+	Field string
+
+	// Desc reports whether field is sorted in descending order
+	Desc bool
+}
+
+func (s *Sort) parseField(d *Decoder, sField reflect.StructField, queryValue string) error {
+	var errs Errors
+
+	sort := Sort{}
+
+	for _, f := range strings.Split(queryValue, getSeparator(sField)) {
+		// unencoded + prefix is decoded as space
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+
+		field := SortField{Field: strings.ToLower(f)}
+
+		switch f[0] {
+		case '-':
+			field = SortField{Field: strings.ToLower(f[1:]), Desc: true}
+		case '+':
+			field.Field = strings.ToLower(f[1:])
+		}
+
+		if field.Field == "" {
+			errs = append(errs, newFieldError(CodeInvalidSort, sField.Name, f, nil))
+			continue
+		}
+
+		sort = append(sort, field)
+	}
+
+	*s = sort
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
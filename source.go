@@ -0,0 +1,133 @@
+package qparams
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ValueSource provides raw param values by name, ok is false
+// if the source has no value for name
+type ValueSource interface {
+	Lookup(name string) (values []string, ok bool)
+}
+
+// SourceFunc adapts lookup func to ValueSource
+type SourceFunc func(name string) ([]string, bool)
+
+// Lookup implements ValueSource
+func (f SourceFunc) Lookup(name string) ([]string, bool) {
+	return f(name)
+}
+
+// Chain is a precedence chain of sources, names are looked up in
+// sources in order and the first source having a value wins eg.
+// qparams.Chain{query, userPreferences, tenantDefaults}
+type Chain []ValueSource
+
+// Lookup implements ValueSource
+func (c Chain) Lookup(name string) ([]string, bool) {
+	for _, s := range c {
+		if s == nil {
+			continue
+		}
+
+		if values, ok := s.Lookup(name); ok {
+			return values, true
+		}
+	}
+
+	return nil, false
+}
+
+// valuesSource looks names up in url.Values case-insensitively
+type valuesSource url.Values
+
+// ValuesSource returns source of url.Values, names are
+// matched case-insensitively. Values are not modified
+func ValuesSource(values url.Values) ValueSource {
+	lowered := make(valuesSource, len(values))
+
+	for key, val := range values {
+		lowered[key] = val
+	}
+
+	for key, val := range values {
+		lowered[strings.ToLower(key)] = val
+	}
+
+	return lowered
+}
+
+// Lookup implements ValueSource
+func (s valuesSource) Lookup(name string) ([]string, bool) {
+	values, ok := s[name]
+	if !ok {
+		values, ok = s[strings.ToLower(name)]
+	}
+
+	return values, ok && len(values) > 0
+}
+
+// HeaderSource returns source of request headers
+func HeaderSource(r *http.Request) ValueSource {
+	return SourceFunc(func(name string) ([]string, bool) {
+		values := r.Header.Values(name)
+		return values, len(values) > 0
+	})
+}
+
+// CookieSource returns source of request cookies
+func CookieSource(r *http.Request) ValueSource {
+	return SourceFunc(func(name string) ([]string, bool) {
+		c, err := r.Cookie(name)
+		if err != nil {
+			return nil, false
+		}
+
+		return []string{c.Value}, true
+	})
+}
+
+// PathSource returns source of request path values
+// set by http.ServeMux patterns eg. /orders/{id}
+func PathSource(r *http.Request) ValueSource {
+	return SourceFunc(func(name string) ([]string, bool) {
+		v := r.PathValue(name)
+		return []string{v}, v != ""
+	})
+}
+
+// RequestSource returns source of request values read by Parse,
+// URL query or form values depending on decoder Source, followed
+// by decoder Sources
+func (d *Decoder) RequestSource(r *http.Request) (ValueSource, error) {
+	values, err := d.requestValues(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.chain(ValuesSource(values)), nil
+}
+
+// chain returns src followed by decoder Sources
+func (d *Decoder) chain(src ValueSource) ValueSource {
+	if len(d.Sources) == 0 {
+		return src
+	}
+
+	return append(Chain{src}, d.Sources...)
+}
+
+// ParseSource will try to parse values of src to provided struct,
+// and will return error on filure. Fields with header, cookie or
+// path source are left empty or set to their default value
+func ParseSource(dest interface{}, src ValueSource) error {
+	return defaultDecoder.ParseSource(dest, src)
+}
+
+// ParseSource will try to parse values of src to provided struct,
+// and will return error on filure. Decoder Sources are not consulted
+func (d *Decoder) ParseSource(dest interface{}, src ValueSource) error {
+	return d.decode(dest, map[string]ValueSource{"query": src})
+}
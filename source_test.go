@@ -0,0 +1,132 @@
+package qparams
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestParseTagSources(t *testing.T) {
+	type testStruct struct {
+		Tenant   string `qparams:"in:header name:X-Tenant"`
+		Lang     string `qparams:"in:header name:Accept-Language default:en"`
+		Session  string `qparams:"in:cookie name:sid"`
+		ID       int    `qparams:"in:path"`
+		Limit    int    `qparams:"default:20"`
+		Page     int    `qparams:"in:query default:1"`
+		Sort     Sort   `qparams:"default:-created"`
+		Filter   Filter `qparams:"in:header name:X-Filter"`
+		Statuses Filter `qparams:"inop:=in="`
+		Scopes   Filter `qparams:"in:header name:X-Scopes inop:=in=,path"`
+	}
+
+	r, _ := http.NewRequest("GET", "foobar.com/orders/42?page=3&statuses=status=in=(a,b)&x-tenant=ignored", nil)
+	r.Header.Set("X-Tenant", "acme")
+	r.Header.Set("X-Filter", "age>=3")
+	r.Header.Set("X-Scopes", "team=in=(a,b),org path(c)")
+	r.AddCookie(&http.Cookie{Name: "sid", Value: "s1"})
+	r.SetPathValue("id", "42")
+
+	var got testStruct
+	if err := Parse(&got, r); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got.Tenant != "acme" || got.Lang != "en" || got.Session != "s1" || got.ID != 42 || got.Limit != 20 || got.Page != 3 {
+		t.Fatalf("Incorrect result GOT: %+v", got)
+	}
+
+	if len(got.Sort) != 1 || got.Sort[0] != (SortField{Field: "created", Desc: true}) {
+		t.Fatalf("Incorrect default sort GOT: %+v", got.Sort)
+	}
+
+	if c := got.Filter.Conditions; len(c) != 1 || c[0].Op != Gte {
+		t.Fatalf("Incorrect header filter GOT: %+v", c)
+	}
+
+	if c := got.Statuses.Conditions; len(c) != 1 || c[0].Op != In {
+		t.Fatalf("Incorrect set filter GOT: %+v", c)
+	}
+
+	if c := got.Scopes.Conditions; len(c) != 2 || c[0].Op != In || c[1].RawOp != "path" || len(c[1].Values) != 1 {
+		t.Fatalf("Incorrect header set filter GOT: %+v", c)
+	}
+
+	r, _ = http.NewRequest("GET", "foobar.com/orders/x", nil)
+	r.SetPathValue("id", "x")
+
+	got = testStruct{}
+	err := Parse(&got, r)
+	if err == nil || err.Error() != "Field ID does not contain a valid integer (x)\n" {
+		t.Fatalf("Incorrect error GOT: %v", err)
+	}
+
+	got = testStruct{}
+	if err := ParseQuery(&got, "tenant=acme&id=1"); err != nil || got.Tenant != "" || got.ID != 0 || got.Lang != "en" {
+		t.Fatalf("Request sources should be empty without request GOT: %+v %v", got, err)
+	}
+}
+
+func TestParseSourceChain(t *testing.T) {
+	type testStruct struct {
+		Limit  int    `qparams:"default:10"`
+		Sort   Sort   `qparams:"name:order"`
+		Filter Filter `qparams:"name:filter"`
+		Tenant string `qparams:"in:header name:X-Tenant"`
+	}
+
+	prefs := SourceFunc(func(name string) ([]string, bool) {
+		if name == "order" {
+			return []string{"-created"}, true
+		}
+		return nil, false
+	})
+
+	tenant := ValuesSource(url.Values{"Limit": {"50"}, "order": {"name"}, "filter": {"active==true"}})
+
+	r, _ := http.NewRequest("GET", "foobar.com/orders?filter=age>=3", nil)
+	r.Header.Set("X-Tenant", "acme")
+
+	d := &Decoder{Sources: []ValueSource{prefs, tenant}}
+
+	var got testStruct
+	if err := d.Parse(&got, r); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got.Limit != 50 || got.Tenant != "acme" {
+		t.Fatalf("Incorrect result GOT: %+v", got)
+	}
+
+	if len(got.Sort) != 1 || got.Sort[0] != (SortField{Field: "created", Desc: true}) {
+		t.Fatalf("Preferences should override tenant defaults GOT: %+v", got.Sort)
+	}
+
+	if c := got.Filter.Conditions; len(c) != 1 || c[0].Op != Gte {
+		t.Fatalf("Query should override tenant defaults GOT: %+v", c)
+	}
+
+	got = testStruct{}
+	if err := ParseSource(&got, Chain{ValuesSource(url.Values{"order": {"id"}}), nil, tenant}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got.Limit != 50 || len(got.Sort) != 1 || got.Sort[0].Field != "id" || got.Tenant != "" {
+		t.Fatalf("Incorrect chain result GOT: %+v", got)
+	}
+
+	got = testStruct{}
+	if err := ParseSource(&got, Chain{}); err != nil || got.Limit != 10 {
+		t.Fatalf("Empty chain should leave defaults GOT: %+v %v", got, err)
+	}
+}
+
+func TestPrepareInvalidSource(t *testing.T) {
+	type testStruct struct {
+		Filter Filter `qparams:"in:=in="`
+	}
+
+	if err := Prepare[testStruct](); err == nil || err.Error() != "Field Filter has invalid in tag =in=" {
+		t.Fatalf("Incorrect error GOT: %v", err)
+	}
+}
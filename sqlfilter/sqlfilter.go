@@ -0,0 +1,354 @@
+// Package sqlfilter translates parsed qparams filters, sort and
+// pagination to parameterized SQL fragments
+//
+// Only fields present in the column allowlist can be filtered or
+// sorted by, values are always passed as arguments
+package sqlfilter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tonto/qparams"
+)
+
+// Dialect describes SQL dialect specifics
+type Dialect struct {
+	// Placeholder returns n-th (1 based) argument placeholder
+	Placeholder func(n int) string
+
+	// Paginate returns pagination clause for limit and offset
+	// placeholders, either of which may be empty. Defaults to
+	// LIMIT OFFSET pagination
+	Paginate func(limit, offset string) string
+
+	// OrderRequired is set if pagination requires ORDER BY clause,
+	// unsorted queries are then ordered by (SELECT NULL)
+	OrderRequired bool
+}
+
+func limitOffset(limit, offset string) string {
+	var clauses []string
+
+	if limit != "" {
+		clauses = append(clauses, "LIMIT "+limit)
+	}
+
+	if offset != "" {
+		clauses = append(clauses, "OFFSET "+offset)
+	}
+
+	return strings.Join(clauses, " ")
+}
+
+// Supported dialects
+var (
+	// Question uses ? placeholders eg. MySQL, SQLite
+	Question = Dialect{
+		Placeholder: func(int) string { return "?" },
+		Paginate:    limitOffset,
+	}
+
+	// Dollar uses $1 placeholders eg. PostgreSQL
+	Dollar = Dialect{
+		Placeholder: func(n int) string { return fmt.Sprintf("$%d", n) },
+		Paginate:    limitOffset,
+	}
+
+	// AtP uses @p1 placeholders and OFFSET FETCH pagination eg. SQL Server,
+	// which requires ORDER BY clause for pagination
+	AtP = Dialect{
+		Placeholder: func(n int) string { return fmt.Sprintf("@p%d", n) },
+		Paginate: func(limit, offset string) string {
+			if offset == "" {
+				offset = "0"
+			}
+
+			clause := fmt.Sprintf("OFFSET %s ROWS", offset)
+			if limit != "" {
+				clause += fmt.Sprintf(" FETCH NEXT %s ROWS ONLY", limit)
+			}
+
+			return clause
+		},
+		OrderRequired: true,
+	}
+)
+
+// Options configure SQL translation
+type Options struct {
+	// Columns maps filter and sort field names to columns,
+	// fields not present are rejected
+	Columns map[string]string
+
+	// Mapper resolves filter and sort field names to columns
+	// eg. qparams.FieldMapOf[Order]("db"), it takes precedence
+	// over Columns
+	Mapper qparams.FieldMapper
+
+	// Dialect defaults to Question
+	Dialect Dialect
+}
+
+// ErrUnknownField is returned for fields not present in Columns
+// or not mapped by Mapper
+var ErrUnknownField = errors.New("sqlfilter: unknown field")
+
+// ErrUnsupportedOp is returned for conditions with Custom operator
+var ErrUnsupportedOp = errors.New("sqlfilter: unsupported operator")
+
+// likeEscape is the escape character of LIKE patterns, ! is not
+// a string literal escape in any dialect unlike \ in MySQL
+const likeEscape = '!'
+
+// Result holds translated SQL fragments and their arguments
+type Result struct {
+	// Where is the WHERE clause condition, without WHERE keyword
+	Where string
+
+	// OrderBy is the ORDER BY clause list, without ORDER BY keyword
+	OrderBy string
+
+	// Pagination is the full pagination clause eg. LIMIT ? OFFSET ?
+	Pagination string
+
+	// Args holds arguments of all placeholders in order
+	Args []interface{}
+}
+
+// String returns the fragment to be appended to SELECT ... FROM ...
+// eg. WHERE a = ? ORDER BY b DESC LIMIT ?
+func (r Result) String() string {
+	var clauses []string
+
+	if r.Where != "" {
+		clauses = append(clauses, "WHERE "+r.Where)
+	}
+
+	if r.OrderBy != "" {
+		clauses = append(clauses, "ORDER BY "+r.OrderBy)
+	}
+
+	if r.Pagination != "" {
+		clauses = append(clauses, r.Pagination)
+	}
+
+	return strings.Join(clauses, " ")
+}
+
+type builder struct {
+	opts Options
+	args []interface{}
+}
+
+// Build translates q to SQL fragments
+func Build(q qparams.Query, opts Options) (Result, error) {
+	if opts.Dialect.Placeholder == nil {
+		opts.Dialect = Question
+	}
+
+	if opts.Dialect.Paginate == nil {
+		opts.Dialect.Paginate = limitOffset
+	}
+
+	b := &builder{opts: opts}
+
+	where, err := b.node(q.Filter.Expr(), false)
+	if err != nil {
+		return Result{}, err
+	}
+
+	orderBy, err := b.orderBy(q.Sort)
+	if err != nil {
+		return Result{}, err
+	}
+
+	var limit, offset string
+
+	if q.Limit > 0 {
+		limit = b.arg(q.Limit)
+	}
+
+	if q.Offset > 0 {
+		offset = b.arg(q.Offset)
+	}
+
+	var pagination string
+	if limit != "" || offset != "" {
+		pagination = opts.Dialect.Paginate(limit, offset)
+
+		if orderBy == "" && opts.Dialect.OrderRequired {
+			orderBy = "(SELECT NULL)"
+		}
+	}
+
+	return Result{
+		Where:      where,
+		OrderBy:    orderBy,
+		Pagination: pagination,
+		Args:       b.args,
+	}, nil
+}
+
+// Where translates filter f to WHERE clause condition
+func Where(f qparams.Filter, opts Options) (string, []interface{}, error) {
+	r, err := Build(qparams.Query{Filter: f}, opts)
+
+	return r.Where, r.Args, err
+}
+
+func (b *builder) arg(v interface{}) string {
+	if d, ok := v.(qparams.Decimal); ok {
+		v = string(d)
+	}
+
+	b.args = append(b.args, v)
+
+	return b.opts.Dialect.Placeholder(len(b.args))
+}
+
+func (b *builder) column(field string) (string, error) {
+	var mapper qparams.FieldMapper = qparams.FieldMap(b.opts.Columns)
+	if b.opts.Mapper != nil {
+		mapper = b.opts.Mapper
+	}
+
+	col, ok := mapper.MapField(field)
+	if !ok {
+		return "", fmt.Errorf("%w %s", ErrUnknownField, field)
+	}
+
+	return col, nil
+}
+
+// node translates n, nested is set for nodes within And or Or
+// which are parenthesized if they combine multiple conditions
+func (b *builder) node(n qparams.Node, nested bool) (string, error) {
+	var nodes []qparams.Node
+	var join string
+
+	switch n := n.(type) {
+	case qparams.And:
+		nodes, join = n, " AND "
+	case qparams.Or:
+		nodes, join = n, " OR "
+	case qparams.Not:
+		s, err := b.node(n.Node, false)
+		if err != nil || s == "" {
+			return s, err
+		}
+		return fmt.Sprintf("NOT (%s)", s), nil
+	case qparams.Condition:
+		return b.condition(n)
+	}
+
+	var parts []string
+
+	for _, n := range nodes {
+		s, err := b.node(n, true)
+		if err != nil {
+			return "", err
+		}
+
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+
+	s := strings.Join(parts, join)
+	if nested && len(parts) > 1 {
+		s = "(" + s + ")"
+	}
+
+	return s, nil
+}
+
+var comparisons = map[qparams.Op]string{
+	qparams.Eq:  "=",
+	qparams.Ne:  "<>",
+	qparams.Gt:  ">",
+	qparams.Gte: ">=",
+	qparams.Lt:  "<",
+	qparams.Lte: "<=",
+}
+
+func (b *builder) condition(c qparams.Condition) (string, error) {
+	col, err := b.column(c.Field)
+	if err != nil {
+		return "", err
+	}
+
+	var s string
+
+	switch {
+	case c.Pattern != nil:
+		pattern := c.Pattern.SQLLike(likeEscape)
+		if c.Pattern.Fold {
+			s = fmt.Sprintf("LOWER(%s) LIKE LOWER(%s) ESCAPE '%c'", col, b.arg(pattern), likeEscape)
+		} else {
+			s = fmt.Sprintf("%s LIKE %s ESCAPE '%c'", col, b.arg(pattern), likeEscape)
+		}
+	case c.Op == qparams.IsNull:
+		if c.Not {
+			return col + " IS NOT NULL", nil
+		}
+		return col + " IS NULL", nil
+	case c.Op == qparams.In:
+		var placeholders []string
+		for _, v := range c.TypedValues() {
+			placeholders = append(placeholders, b.arg(v))
+		}
+
+		in := "IN"
+		if c.Not {
+			in = "NOT IN"
+		}
+
+		return fmt.Sprintf("%s %s (%s)", col, in, strings.Join(placeholders, ", ")), nil
+	case c.Range != nil:
+		lower, upper := c.TypedBounds()
+		switch {
+		case lower != nil && upper != nil:
+			s = fmt.Sprintf("%s BETWEEN %s AND %s", col, b.arg(lower), b.arg(upper))
+		case lower != nil:
+			s = fmt.Sprintf("%s >= %s", col, b.arg(lower))
+		default:
+			s = fmt.Sprintf("%s <= %s", col, b.arg(upper))
+		}
+	default:
+		op, ok := comparisons[c.Op]
+		if !ok {
+			return "", fmt.Errorf("%w %s", ErrUnsupportedOp, c.RawOp)
+		}
+
+		s = fmt.Sprintf("%s %s %s", col, op, b.arg(c.TypedValue()))
+	}
+
+	if c.Not {
+		s = fmt.Sprintf("NOT (%s)", s)
+	}
+
+	return s, nil
+}
+
+func (b *builder) orderBy(sort qparams.Sort) (string, error) {
+	var fields []string
+
+	for _, f := range sort {
+		col, err := b.column(f.Field)
+		if err != nil {
+			return "", err
+		}
+
+		if f.Desc {
+			col += " DESC"
+		} else {
+			col += " ASC"
+		}
+
+		fields = append(fields, col)
+	}
+
+	return strings.Join(fields, ", "), nil
+}
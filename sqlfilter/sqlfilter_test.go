@@ -0,0 +1,234 @@
+package sqlfilter
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/tonto/qparams"
+)
+
+var columns = map[string]string{
+	"amount":  "o.amount",
+	"age":     "c.age",
+	"status":  "o.status",
+	"name":    "c.name",
+	"deleted": "o.deleted_at",
+	"created": "o.created_at",
+}
+
+// cond returns condition of field, operator and value
+func cond(field string, op qparams.Op, value string) qparams.Condition {
+	return qparams.Condition{Field: field, Op: op, Value: value}
+}
+
+// where returns query filtering by expression root
+func where(root qparams.Node) qparams.Query {
+	return qparams.Query{Filter: qparams.Filter{Root: root}}
+}
+
+func TestBuild(t *testing.T) {
+	amount := cond("amount", qparams.Gte, "10.5")
+	amount.Typed = qparams.Decimal("10.5")
+
+	age := cond("age", qparams.Gt, "18")
+	age.Typed = 18
+
+	paginated := where(qparams.And{
+		qparams.Or{cond("status", qparams.Eq, "paid"), cond("status", qparams.Eq, "refunded")},
+		age,
+	})
+	paginated.Sort = qparams.Sort{{Field: "created", Desc: true}, {Field: "name"}}
+	paginated.Limit, paginated.Offset = 10, 20
+
+	sets := where(qparams.And{
+		qparams.Condition{Field: "status", Op: qparams.In, Values: []string{"paid", "refunded"}},
+		qparams.Condition{Field: "age", Op: qparams.In, Not: true, Values: []string{"1", "2"}, Typed: []int{1, 2}},
+		qparams.Condition{Field: "deleted", Op: qparams.IsNull},
+		qparams.Condition{Field: "created", Op: qparams.IsNull, Not: true},
+	})
+	sets.Limit = 5
+
+	sorted := qparams.Query{Sort: qparams.Sort{{Field: "amount", Desc: true}}, Offset: 10}
+
+	table := []struct {
+		Name    string
+		Query   qparams.Query
+		Dialect Dialect
+		SQL     string
+		Args    []interface{}
+	}{
+		{
+			Name:  "comparisons",
+			Query: where(qparams.And{amount, cond("status", qparams.Eq, "paid")}),
+			SQL:   "WHERE o.amount >= ? AND o.status = ?",
+			Args:  []interface{}{"10.5", "paid"},
+		},
+
+		{
+			Name:    "paginated",
+			Query:   paginated,
+			Dialect: Dollar,
+			SQL:     "WHERE (o.status = $1 OR o.status = $2) AND c.age > $3 ORDER BY o.created_at DESC, c.name ASC LIMIT $4 OFFSET $5",
+			Args:    []interface{}{"paid", "refunded", 18, 10, 20},
+		},
+
+		{
+			Name:    "sets",
+			Query:   sets,
+			Dialect: AtP,
+			SQL:     "WHERE o.status IN (@p1, @p2) AND c.age NOT IN (@p3, @p4) AND o.deleted_at IS NULL AND o.created_at IS NOT NULL ORDER BY (SELECT NULL) OFFSET 0 ROWS FETCH NEXT @p5 ROWS ONLY",
+			Args:    []interface{}{"paid", "refunded", 1, 2, 5},
+		},
+
+		{
+			Name:    "sorted",
+			Query:   sorted,
+			Dialect: AtP,
+			SQL:     "ORDER BY o.amount DESC OFFSET @p1 ROWS",
+			Args:    []interface{}{10},
+		},
+
+		{
+			Name:    "custom dialect",
+			Query:   qparams.Query{Limit: 5},
+			Dialect: Dialect{Placeholder: func(n int) string { return fmt.Sprintf(":%d", n) }},
+			SQL:     "LIMIT :1",
+			Args:    []interface{}{5},
+		},
+
+		{
+			Name: "ranges",
+			Query: where(qparams.And{
+				qparams.Condition{Field: "age", Op: qparams.Between, Range: &qparams.Bounds{Lower: "18", Upper: "65"}, Typed: [2]interface{}{18, 65}},
+				qparams.Condition{Field: "amount", Op: qparams.Between, Range: &qparams.Bounds{Upper: "100"}},
+				qparams.Condition{Field: "created", Op: qparams.Between, Range: &qparams.Bounds{Lower: "2024-01-01"}},
+			}),
+			SQL:  "WHERE c.age BETWEEN ? AND ? AND o.amount <= ? AND o.created_at >= ?",
+			Args: []interface{}{18, 65, "100", "2024-01-01"},
+		},
+
+		{
+			Name: "patterns",
+			Query: where(qparams.And{
+				qparams.Condition{Field: "name", Op: qparams.Like, Pattern: &qparams.Pattern{Mode: qparams.MatchLike, Parts: []string{"Jo", "_%"}}},
+				qparams.Condition{Field: "name", Op: qparams.Contains, Pattern: &qparams.Pattern{Mode: qparams.MatchContains, Parts: []string{"50%"}}},
+				qparams.Condition{Field: "name", Op: qparams.ILike, Pattern: &qparams.Pattern{Mode: qparams.MatchLike, Parts: []string{"", "DOE!"}, Fold: true}},
+				qparams.Condition{Field: "status", Op: qparams.Eq, Value: "paid", Not: true},
+			}),
+			SQL:  `WHERE c.name LIKE ? ESCAPE '!' AND c.name LIKE ? ESCAPE '!' AND LOWER(c.name) LIKE LOWER(?) ESCAPE '!' AND NOT (o.status = ?)`,
+			Args: []interface{}{`Jo%!_!%`, `%50!%%`, "%DOE!!", "paid"},
+		},
+
+		{
+			Name:  "negated group",
+			Query: where(qparams.Not{Node: qparams.Or{cond("status", qparams.Eq, "paid"), cond("age", qparams.Lt, "3")}}),
+			SQL:   "WHERE NOT (o.status = ? OR c.age < ?)",
+			Args:  []interface{}{"paid", "3"},
+		},
+
+		{
+			Name:  "sort",
+			Query: qparams.Query{Sort: qparams.Sort{{Field: "name"}}},
+			SQL:   "ORDER BY c.name ASC",
+			Args:  nil,
+		},
+	}
+
+	for _, c := range table {
+		r, err := Build(c.Query, Options{Columns: columns, Dialect: c.Dialect})
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", c.Name, err)
+		}
+
+		if r.String() != c.SQL {
+			t.Fatalf("Incorrect SQL for %s\nWANT: %s\nGOT:  %s", c.Name, c.SQL, r.String())
+		}
+
+		if !reflect.DeepEqual(r.Args, c.Args) {
+			t.Fatalf("Incorrect args for %s WANT: %#v GOT: %#v", c.Name, c.Args, r.Args)
+		}
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	table := []struct {
+		Query qparams.Query
+		Err   error
+	}{
+		{Query: where(cond("password", qparams.Eq, "secret")), Err: ErrUnknownField},
+		{Query: qparams.Query{Sort: qparams.Sort{{Field: "password"}}}, Err: ErrUnknownField},
+		{Query: where(qparams.Condition{Field: "age", Op: qparams.Custom, RawOp: "~~", Value: "1"}), Err: ErrUnsupportedOp},
+	}
+
+	for i, c := range table {
+		if _, err := Build(c.Query, Options{Columns: columns}); !errors.Is(err, c.Err) {
+			t.Fatalf("Incorrect error for case %d WANT: %v GOT: %v", i, c.Err, err)
+		}
+	}
+}
+
+func TestWhere(t *testing.T) {
+	age := cond("age", qparams.Gte, "3")
+	age.Typed = 3
+
+	where, args, err := Where(qparams.Filter{Conditions: []qparams.Condition{age}}, Options{Columns: columns, Dialect: Dollar})
+
+	if err != nil || where != "c.age >= $1" || !reflect.DeepEqual(args, []interface{}{3}) {
+		t.Fatalf("Incorrect where WANT: c.age >= $1 [3] GOT: %s %v %v", where, args, err)
+	}
+}
+
+func TestBuildMapper(t *testing.T) {
+	type order struct {
+		Created string `qparams:"name:createdat" db:"orders.created_at"`
+		Status  string `db:"orders.status"`
+		Secret  string
+	}
+
+	mapper, err := qparams.FieldMapOf[order]("db")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	q := where(qparams.And{cond("createdat", qparams.Gte, "2024-01-01"), cond("status", qparams.Eq, "paid")})
+	q.Sort = qparams.Sort{{Field: "createdat", Desc: true}}
+
+	r, err := Build(q, Options{Mapper: mapper})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := "WHERE orders.created_at >= ? AND orders.status = ? ORDER BY orders.created_at DESC"
+	if r.String() != want {
+		t.Fatalf("Incorrect SQL WANT: %s GOT: %s", want, r.String())
+	}
+
+	if _, err := Build(where(cond("secret", qparams.Eq, "x")), Options{Mapper: mapper}); !errors.Is(err, ErrUnknownField) {
+		t.Fatalf("Incorrect error WANT: %v GOT: %v", ErrUnknownField, err)
+	}
+}
+
+// TestBuildParsed is a smoke test of translating a parsed query
+func TestBuildParsed(t *testing.T) {
+	var p struct {
+		Filter qparams.Filter `qparams:"ops:==,>= inop:=in= expr:true types:age=int"`
+		Sort   qparams.Sort
+		Limit  int
+	}
+
+	if err := qparams.ParseQuery(&p, "filter=(status==paid|age>=18),name=in=(a,b)&sort=-age&limit=5"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	r, err := Build(qparams.Query{Filter: p.Filter, Sort: p.Sort, Limit: p.Limit}, Options{Columns: columns})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := "WHERE (o.status = ? OR c.age >= ?) AND c.name IN (?, ?) ORDER BY c.age DESC LIMIT ?"
+	if r.String() != want || !reflect.DeepEqual(r.Args, []interface{}{"paid", 18, "a", "b", 5}) {
+		t.Fatalf("Incorrect SQL WANT: %s GOT: %s %v", want, r.String(), r.Args)
+	}
+}
@@ -0,0 +1,61 @@
+package qparams
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestParseSources(t *testing.T) {
+	type testStruct struct {
+		Limit  int
+		Embed  Slice
+		Filter Filter
+	}
+
+	want := testStruct{
+		Limit:  10,
+		Embed:  Slice{"a", "b"},
+		Filter: Filter{Conditions: []Condition{{Field: "age", Op: Gte, RawOp: ">=", Value: "3"}}},
+	}
+
+	values := url.Values{"Limit": {"10"}, "embed": {"a,b"}, "filter": {"age>=3"}}
+
+	u, _ := url.Parse("https://foobar.com/orders?limit=10&embed=a,b&filter=age%3E%3D3")
+
+	table := []struct {
+		name  string
+		parse func(dest interface{}) error
+	}{
+		{"values", func(dest interface{}) error { return ParseValues(dest, values) }},
+		{"query", func(dest interface{}) error { return ParseQuery(dest, "limit=10&embed=a,b&filter=age%3E%3D3") }},
+		{"query with ?", func(dest interface{}) error { return ParseQuery(dest, "?limit=10&embed=a,b&filter=age>=3") }},
+		{"url", func(dest interface{}) error { return ParseURL(dest, u) }},
+		{"request", func(dest interface{}) error { return Parse(dest, newRequest(u.String())) }},
+	}
+
+	for _, c := range table {
+		var got testStruct
+
+		if err := c.parse(&got); err != nil {
+			t.Fatalf("Unexpected error for %s: %v", c.name, err)
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("Incorrect result for %s WANT: %+v GOT: %+v", c.name, want, got)
+		}
+	}
+
+	if _, ok := values["limit"]; ok {
+		t.Fatalf("Values should not be modified")
+	}
+
+	var got testStruct
+	if err := ParseQuery(&got, "limit=x"); err == nil || err.Error() != "Field Limit does not contain a valid integer (x)\n" {
+		t.Fatalf("Incorrect error GOT: %v", err)
+	}
+
+	if err := ParseQuery(got, "limit=1"); err != ErrWrongDestType {
+		t.Fatalf("Incorrect error WANT: %v GOT: %v", ErrWrongDestType, err)
+	}
+}
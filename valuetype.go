@@ -0,0 +1,264 @@
+package qparams
+
+import (
+	"cmp"
+	"fmt"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Decimal represents a base 10 decimal number, it is kept as the
+// validated string so no precision is lost to float64 rounding
+type Decimal string
+
+var decimalRegexp = regexp.MustCompile(`^[-+]?([0-9]+(\.[0-9]*)?|\.[0-9]+)$`)
+
+// Float64 will attempt to convert decimal to float64
+func (d Decimal) Float64() (float64, error) {
+	return strconv.ParseFloat(string(d), 64)
+}
+
+func (d *Decimal) parseField(_ *Decoder, sField reflect.StructField, queryValue string) error {
+	if !decimalRegexp.MatchString(queryValue) {
+		return newFieldError(CodeInvalidDecimal, sField.Name, queryValue, nil)
+	}
+
+	*d = Decimal(queryValue)
+
+	return nil
+}
+
+// valueType describes the type filter condition values of a single
+// field are converted to
+type valueType struct {
+	kind   string
+	layout string
+	enum   []string
+}
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	decimalType = reflect.TypeOf(Decimal(""))
+)
+
+func (vt valueType) convert(v string) (interface{}, error) {
+	switch vt.kind {
+	case "int":
+		return strconv.Atoi(v)
+	case "float":
+		return strconv.ParseFloat(v, 64)
+	case "decimal":
+		if !decimalRegexp.MatchString(v) {
+			return nil, fmt.Errorf("invalid decimal %s", v)
+		}
+		return Decimal(v), nil
+	case "bool":
+		return strconv.ParseBool(v)
+	case "time":
+		return time.Parse(vt.layout, v)
+	case "enum":
+		for _, e := range vt.enum {
+			if e == v {
+				return v, nil
+			}
+		}
+		return nil, fmt.Errorf("%s is not one of %s", v, strings.Join(vt.enum, ", "))
+	}
+
+	return v, nil
+}
+
+// matches reports whether v has the type values are converted to
+func (vt valueType) matches(v interface{}) bool {
+	switch v.(type) {
+	case int:
+		return vt.kind == "int"
+	case float64:
+		return vt.kind == "float"
+	case Decimal:
+		return vt.kind == "decimal"
+	case bool:
+		return vt.kind == "bool"
+	case time.Time:
+		return vt.kind == "time"
+	case string:
+		return vt.kind == "string" || vt.kind == "enum"
+	}
+
+	return false
+}
+
+func (vt valueType) String() string {
+	switch vt.kind {
+	case "time":
+		return fmt.Sprintf("time (%s)", vt.layout)
+	case "enum":
+		return fmt.Sprintf("value, one of %s", strings.Join(vt.enum, ", "))
+	}
+
+	return vt.kind
+}
+
+// parseValueType parses type spec eg. int, time(2006-01-02)
+// or enum(paid|refunded)
+func parseValueType(spec string) (valueType, error) {
+	kind, arg := spec, ""
+
+	if i := strings.Index(spec, "("); i != -1 && strings.HasSuffix(spec, ")") {
+		kind, arg = spec[:i], spec[i+1:len(spec)-1]
+	}
+
+	switch kind {
+	case "string", "int", "float", "decimal", "bool":
+		return valueType{kind: kind}, nil
+	case "time":
+		if arg == "" {
+			arg = time.RFC3339
+		}
+		return valueType{kind: kind, layout: arg}, nil
+	case "enum":
+		if arg == "" {
+			break
+		}
+		return valueType{kind: kind, enum: strings.Split(arg, "|")}, nil
+	}
+
+	return valueType{}, fmt.Errorf("Unknown filter value type %s", spec)
+}
+
+// getValueTypes parses the types tag
+// eg. `qparams:"types:age=int,created=time(2006-01-02)"`
+func getValueTypes(sField reflect.StructField) (map[string]valueType, error) {
+	tag := getTag("types", sField)
+	if tag == "" {
+		return nil, nil
+	}
+
+	types := make(map[string]valueType)
+
+	for _, t := range strings.Split(tag, mapOpsTagSeparator) {
+		kv := strings.SplitN(t, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("Field %s has invalid filter type %s", sField.Name, t)
+		}
+
+		vt, err := parseValueType(kv[1])
+		if err != nil {
+			return nil, err
+		}
+
+		types[strings.ToLower(kv[0])] = vt
+	}
+
+	return types, nil
+}
+
+// structValueTypes derives filter value types from the fields
+// of struct type t
+func structValueTypes(t reflect.Type) (map[string]valueType, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("Filter schema %s must be a struct", t)
+	}
+
+	types := make(map[string]valueType)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name := strings.ToLower(field.Name)
+		if tagName := getTag("name", field); tagName != "" {
+			name = tagName
+		}
+
+		vt, ok := fieldValueType(field)
+		if !ok {
+			return nil, fmt.Errorf("Filter schema field %s has unsupported type %s", field.Name, field.Type)
+		}
+
+		types[name] = vt
+	}
+
+	return types, nil
+}
+
+// fieldValueType returns the value type matching the type of
+// field, honouring layout and enum tags
+func fieldValueType(field reflect.StructField) (valueType, bool) {
+	t := field.Type
+
+	switch {
+	case t == timeType:
+		vt := valueType{kind: "time", layout: time.RFC3339}
+		if layout := getTag("layout", field); layout != "" {
+			vt.layout = layout
+		}
+		return vt, true
+	case t == decimalType:
+		return valueType{kind: "decimal"}, true
+	case t.Kind() == reflect.String:
+		if enum := getTag("enum", field); enum != "" {
+			return valueType{kind: "enum", enum: strings.Split(enum, "|")}, true
+		}
+		return valueType{kind: "string"}, true
+	case t.Kind() == reflect.Bool:
+		return valueType{kind: "bool"}, true
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Int64:
+		return valueType{kind: "int"}, true
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		return valueType{kind: "float"}, true
+	}
+
+	return valueType{}, false
+}
+
+// compareValues compares two converted values of the same type,
+// ok is false if the values are not comparable
+func compareValues(a, b interface{}) (c int, ok bool) {
+	switch a := a.(type) {
+	case int:
+		if b, ok := b.(int); ok {
+			return cmp.Compare(a, b), true
+		}
+	case float64:
+		if b, ok := b.(float64); ok {
+			return cmp.Compare(a, b), true
+		}
+	case string:
+		if b, ok := b.(string); ok {
+			return cmp.Compare(a, b), true
+		}
+	case bool:
+		if b, ok := b.(bool); ok {
+			return cmp.Compare(boolInt(a), boolInt(b)), true
+		}
+	case time.Time:
+		if b, ok := b.(time.Time); ok {
+			return a.Compare(b), true
+		}
+	case Decimal:
+		if b, ok := b.(Decimal); ok {
+			ra, okA := new(big.Rat).SetString(string(a))
+			rb, okB := new(big.Rat).SetString(string(b))
+			if okA && okB {
+				return ra.Cmp(rb), true
+			}
+		}
+	}
+
+	return 0, false
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+
+	return 0
+}